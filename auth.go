@@ -0,0 +1,205 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	// AuthMethod logs a client in to Vault. Set Client.Auth to have the client log in again automatically when a
+	// request is rejected as forbidden, ie because the token expired. The client stores the token of the returned
+	// AuthResult, so implementations need not set Client.Token themselves.
+	AuthMethod interface {
+		Login(ctx context.Context, c *Client) (*AuthResult, error)
+	}
+
+	// AuthMethodFunc adapts an ordinary function to the AuthMethod interface.
+	AuthMethodFunc func(ctx context.Context, c *Client) (*AuthResult, error)
+
+	// authState coordinates logins so that concurrent requests failing with the same expired token share a single
+	// login. Logins are scoped to a namespace, so copies returned by WithNamespace get their own.
+	authState struct {
+		mu       sync.RWMutex
+		inflight *authCall
+	}
+
+	// authCall is a login shared by the callers waiting on it. It runs on a context detached from theirs, which is
+	// canceled once every caller has given up.
+	authCall struct {
+		done    chan struct{}
+		result  *AuthResult
+		err     error
+		waiters int
+		cancel  context.CancelFunc
+	}
+
+	// detachedContext carries the values of its parent without being canceled along with it.
+	detachedContext struct {
+		parent context.Context
+	}
+
+	loginContextKey struct{}
+)
+
+func (f AuthMethodFunc) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	return f(ctx, c)
+}
+
+// AuthResult is the "auth" block Vault returns when a token is created, renewed or issued by an auth method login.
+type AuthResult struct {
+	ClientToken      string            `json:"client_token"`
+	Accessor         string            `json:"accessor"`
+	Policies         []string          `json:"policies"`
+	TokenPolicies    []string          `json:"token_policies"`
+	IdentityPolicies []string          `json:"identity_policies"`
+	Metadata         map[string]string `json:"metadata"`
+	LeaseDuration    int               `json:"lease_duration"`
+	Renewable        bool              `json:"renewable"`
+	EntityID         string            `json:"entity_id"`
+	TokenType        string            `json:"token_type"`
+	Orphan           bool              `json:"orphan"`
+}
+
+// TTL returns the lease duration of the token.
+func (a *AuthResult) TTL() time.Duration {
+	return time.Duration(a.LeaseDuration) * time.Second
+}
+
+// parseAuth decodes the auth block of a Vault response.
+func parseAuth(r *vaultResponse) (*AuthResult, error) {
+	if r == nil || r.Auth == nil {
+		return nil, errors.New("no auth data returned")
+	}
+	v := new(AuthResult)
+	if err := typeConvert(r.Auth, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Login logs the client in using c.Auth and stores the resulting token on the client. Concurrent calls, including
+// automatic re-authentication, share a single login.
+func (c *Client) Login(ctx context.Context) (*AuthResult, error) {
+	if c.Auth == nil {
+		return nil, errors.New("no auth method configured")
+	}
+	return c.login(ctx)
+}
+
+// reauthenticate logs in again after a request made with staleToken was forbidden, unless another caller has
+// already replaced the token in the meantime.
+func (c *Client) reauthenticate(ctx context.Context, staleToken string) error {
+	if c.token() != staleToken {
+		return nil
+	}
+	_, err := c.login(ctx)
+	return err
+}
+
+func (c *Client) login(ctx context.Context) (*AuthResult, error) {
+	if c.auth == nil {
+		result, err := c.Auth.Login(withLogin(ctx), c)
+		if err != nil {
+			return nil, err
+		}
+		c.SetToken(result.ClientToken)
+		return result, nil
+	}
+
+	c.auth.mu.Lock()
+	call := c.auth.inflight
+	if call == nil {
+		loginCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+		call = &authCall{done: make(chan struct{}), cancel: cancel}
+		c.auth.inflight = call
+		go c.runLogin(loginCtx, call)
+	}
+	call.waiters++
+	c.auth.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		c.auth.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// nobody is left waiting, so stop the login and let the next caller start over
+			call.cancel()
+			if c.auth.inflight == call {
+				c.auth.inflight = nil
+			}
+		}
+		c.auth.mu.Unlock()
+		return nil, &ErrCanceled{Err: ctx.Err()}
+	case <-call.done:
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	c.SetToken(call.result.ClientToken)
+	return call.result, nil
+}
+
+// runLogin performs a shared login, then wakes up its callers.
+func (c *Client) runLogin(ctx context.Context, call *authCall) {
+	defer call.cancel()
+	call.result, call.err = c.Auth.Login(withLogin(ctx), c)
+	if call.err == nil && call.result == nil {
+		call.err = errors.New("auth method returned no auth data")
+	}
+
+	c.auth.mu.Lock()
+	if c.auth.inflight == call {
+		c.auth.inflight = nil
+	}
+	c.auth.mu.Unlock()
+	close(call.done)
+}
+
+// token returns the client's current token.
+func (c *Client) token() string {
+	if c.auth == nil {
+		return c.Token
+	}
+	c.auth.mu.RLock()
+	defer c.auth.mu.RUnlock()
+	return c.Token
+}
+
+// SetToken replaces the client's token. Once the client is in use, call it instead of assigning Client.Token, which
+// requests and automatic re-authentication read and replace concurrently.
+func (c *Client) SetToken(token string) {
+	if c.auth == nil {
+		c.Token = token
+		return
+	}
+	c.auth.mu.Lock()
+	defer c.auth.mu.Unlock()
+	c.Token = token
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+// withLogin marks ctx as belonging to a login request, which is never retried by logging in again.
+func withLogin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loginContextKey{}, true)
+}
+
+func isLogin(ctx context.Context) bool {
+	return ctx.Value(loginContextKey{}) != nil
+}
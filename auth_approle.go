@@ -0,0 +1,297 @@
+package govault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+)
+
+const DefaultAppRoleMountPath = "approle"
+
+type (
+	AppRole interface {
+		WithMountPath(path string) AppRole
+		Login(roleID, secretID string) (*AuthResult, error)
+		LoginCtx(ctx context.Context, roleID, secretID string) (*AuthResult, error)
+		CreateOrUpdateRole(name string, role *AppRoleRole) error
+		CreateOrUpdateRoleCtx(ctx context.Context, name string, role *AppRoleRole) error
+		ReadRole(name string) (*AppRoleRole, error)
+		ReadRoleCtx(ctx context.Context, name string) (*AppRoleRole, error)
+		ListRoles() ([]string, error)
+		ListRolesCtx(ctx context.Context) ([]string, error)
+		DeleteRole(name string) error
+		DeleteRoleCtx(ctx context.Context, name string) error
+		ReadRoleID(name string) (string, error)
+		ReadRoleIDCtx(ctx context.Context, name string) (string, error)
+		GenerateSecretID(name string, options *AppRoleSecretIDOptions) (*AppRoleSecretID, error)
+		GenerateSecretIDCtx(ctx context.Context, name string, options *AppRoleSecretIDOptions) (*AppRoleSecretID, error)
+		LookupSecretID(name, secretID string) (*AppRoleSecretIDInfo, error)
+		LookupSecretIDCtx(ctx context.Context, name, secretID string) (*AppRoleSecretIDInfo, error)
+		DestroySecretID(name, secretID string) error
+		DestroySecretIDCtx(ctx context.Context, name, secretID string) error
+	}
+
+	appRoleImpl struct {
+		client    *Client
+		MountPath string
+	}
+
+	// AppRoleLogin is an AuthMethod that logs in with a role ID and secret ID. MountPath defaults to
+	// DefaultAppRoleMountPath.
+	AppRoleLogin struct {
+		MountPath string
+		RoleID    string
+		SecretID  string
+	}
+
+	// AppRoleRole is the configuration of an AppRole role. Durations are in seconds. BindSecretID defaults to true
+	// when left nil.
+	AppRoleRole struct {
+		BindSecretID       *bool    `json:"bind_secret_id,omitempty"`
+		SecretIDBoundCIDRs []string `json:"secret_id_bound_cidrs,omitempty"`
+		SecretIDNumUses    int      `json:"secret_id_num_uses,omitempty"`
+		SecretIDTTL        int      `json:"secret_id_ttl,omitempty"`
+		LocalSecretIDs     bool     `json:"local_secret_ids,omitempty"`
+		TokenTTL           int      `json:"token_ttl,omitempty"`
+		TokenMaxTTL        int      `json:"token_max_ttl,omitempty"`
+		TokenPolicies      []string `json:"token_policies,omitempty"`
+		TokenBoundCIDRs    []string `json:"token_bound_cidrs,omitempty"`
+		TokenNumUses       int      `json:"token_num_uses,omitempty"`
+		TokenPeriod        int      `json:"token_period,omitempty"`
+		TokenType          string   `json:"token_type,omitempty"`
+	}
+
+	// AppRoleSecretIDOptions are the parameters of a secret ID generate request. TTL is in seconds.
+	AppRoleSecretIDOptions struct {
+		Metadata        map[string]string
+		CIDRList        []string
+		TokenBoundCIDRs []string
+		NumUses         int
+		TTL             int
+	}
+
+	AppRoleSecretID struct {
+		SecretID         string `json:"secret_id"`
+		SecretIDAccessor string `json:"secret_id_accessor"`
+		SecretIDTTL      int    `json:"secret_id_ttl"`
+		SecretIDNumUses  int    `json:"secret_id_num_uses"`
+	}
+
+	AppRoleSecretIDInfo struct {
+		CIDRList         []string          `json:"cidr_list"`
+		CreationTime     string            `json:"creation_time"`
+		ExpirationTime   string            `json:"expiration_time"`
+		LastUpdatedTime  string            `json:"last_updated_time"`
+		Metadata         map[string]string `json:"metadata"`
+		SecretIDAccessor string            `json:"secret_id_accessor"`
+		SecretIDNumUses  int               `json:"secret_id_num_uses"`
+		SecretIDTTL      int               `json:"secret_id_ttl"`
+		TokenBoundCIDRs  []string          `json:"token_bound_cidrs"`
+	}
+)
+
+func (c *Client) AppRole() AppRole {
+	return &appRoleImpl{
+		client:    c,
+		MountPath: DefaultAppRoleMountPath,
+	}
+}
+
+func (a *appRoleImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return a.client.doV1(ctx, method, path.Join("auth", a.MountPath, endpoint), params, body)
+}
+
+func (a *appRoleImpl) WithMountPath(path string) AppRole {
+	aCopy := *a
+	aCopy.MountPath = path
+	a.client.Logger.Debug("using mount path: " + path)
+	return &aCopy
+}
+
+// Login exchanges a role ID and secret ID for a token, which is also set as the client's token.
+// vault command: `vault write auth/approle/login role_id={roleID} secret_id={secretID}`
+func (a *appRoleImpl) Login(roleID, secretID string) (*AuthResult, error) {
+	return a.LoginCtx(context.Background(), roleID, secretID)
+}
+
+func (a *appRoleImpl) LoginCtx(ctx context.Context, roleID, secretID string) (*AuthResult, error) {
+	ctx = withLogin(ctx)
+	body := map[string]interface{}{"role_id": roleID}
+	if secretID != "" {
+		body["secret_id"] = secretID
+	}
+	r, err := a.do(ctx, http.MethodPost, "login", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := parseAuth(r)
+	if err != nil {
+		return nil, err
+	}
+	a.client.SetToken(auth.ClientToken)
+	return auth, nil
+}
+
+func (l *AppRoleLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	a := c.AppRole()
+	if l.MountPath != "" {
+		a = a.WithMountPath(l.MountPath)
+	}
+	return a.LoginCtx(ctx, l.RoleID, l.SecretID)
+}
+
+// vault command: `vault write auth/approle/role/{name} token_policies={policies}`
+func (a *appRoleImpl) CreateOrUpdateRole(name string, role *AppRoleRole) error {
+	return a.CreateOrUpdateRoleCtx(context.Background(), name, role)
+}
+
+func (a *appRoleImpl) CreateOrUpdateRoleCtx(ctx context.Context, name string, role *AppRoleRole) error {
+	r, err := a.do(ctx, http.MethodPost, "role/"+name, nil, role)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	a.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/approle/role/{name}`
+func (a *appRoleImpl) ReadRole(name string) (*AppRoleRole, error) {
+	return a.ReadRoleCtx(context.Background(), name)
+}
+
+func (a *appRoleImpl) ReadRoleCtx(ctx context.Context, name string) (*AppRoleRole, error) {
+	r, err := a.do(ctx, http.MethodGet, "role/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.client.Logger.Trace(r)
+	v := new(AppRoleRole)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault list auth/approle/role`
+func (a *appRoleImpl) ListRoles() ([]string, error) {
+	return a.ListRolesCtx(context.Background())
+}
+
+func (a *appRoleImpl) ListRolesCtx(ctx context.Context) ([]string, error) {
+	r, err := a.do(ctx, http.MethodGet, "role", newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	a.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault delete auth/approle/role/{name}`
+func (a *appRoleImpl) DeleteRole(name string) error {
+	return a.DeleteRoleCtx(context.Background(), name)
+}
+
+func (a *appRoleImpl) DeleteRoleCtx(ctx context.Context, name string) error {
+	r, err := a.do(ctx, http.MethodDelete, "role/"+name, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	a.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/approle/role/{name}/role-id`
+func (a *appRoleImpl) ReadRoleID(name string) (string, error) {
+	return a.ReadRoleIDCtx(context.Background(), name)
+}
+
+func (a *appRoleImpl) ReadRoleIDCtx(ctx context.Context, name string) (string, error) {
+	r, err := a.do(ctx, http.MethodGet, "role/"+name+"/role-id", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	a.client.Logger.Trace(r)
+	data := new(struct {
+		RoleID string `json:"role_id"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return "", err
+	}
+	return data.RoleID, nil
+}
+
+// vault command: `vault write -f auth/approle/role/{name}/secret-id`
+func (a *appRoleImpl) GenerateSecretID(name string, options *AppRoleSecretIDOptions) (*AppRoleSecretID, error) {
+	return a.GenerateSecretIDCtx(context.Background(), name, options)
+}
+
+func (a *appRoleImpl) GenerateSecretIDCtx(ctx context.Context, name string, options *AppRoleSecretIDOptions) (*AppRoleSecretID, error) {
+	body := map[string]interface{}{}
+	if options != nil {
+		// Vault expects the metadata as a JSON-encoded string
+		if options.Metadata != nil {
+			b, err := json.Marshal(options.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			body["metadata"] = string(b)
+		}
+		if len(options.CIDRList) > 0 {
+			body["cidr_list"] = options.CIDRList
+		}
+		if len(options.TokenBoundCIDRs) > 0 {
+			body["token_bound_cidrs"] = options.TokenBoundCIDRs
+		}
+		if options.NumUses > 0 {
+			body["num_uses"] = options.NumUses
+		}
+		if options.TTL > 0 {
+			body["ttl"] = options.TTL
+		}
+	}
+	r, err := a.do(ctx, http.MethodPost, "role/"+name+"/secret-id", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	v := new(AppRoleSecretID)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault write auth/approle/role/{name}/secret-id/lookup secret_id={secretID}`
+func (a *appRoleImpl) LookupSecretID(name, secretID string) (*AppRoleSecretIDInfo, error) {
+	return a.LookupSecretIDCtx(context.Background(), name, secretID)
+}
+
+func (a *appRoleImpl) LookupSecretIDCtx(ctx context.Context, name, secretID string) (*AppRoleSecretIDInfo, error) {
+	body := map[string]interface{}{"secret_id": secretID}
+	r, err := a.do(ctx, http.MethodPost, "role/"+name+"/secret-id/lookup", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	a.client.Logger.Trace(r)
+	v := new(AppRoleSecretIDInfo)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault write auth/approle/role/{name}/secret-id/destroy secret_id={secretID}`
+func (a *appRoleImpl) DestroySecretID(name, secretID string) error {
+	return a.DestroySecretIDCtx(context.Background(), name, secretID)
+}
+
+func (a *appRoleImpl) DestroySecretIDCtx(ctx context.Context, name, secretID string) error {
+	body := map[string]interface{}{"secret_id": secretID}
+	r, err := a.do(ctx, http.MethodPost, "role/"+name+"/secret-id/destroy", nil, body)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	a.client.Logger.Trace(r)
+	return nil
+}
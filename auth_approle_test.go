@@ -0,0 +1,162 @@
+package govault
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_appRoleImpl_Login(t *testing.T) {
+	tests := []struct {
+		name      string
+		mountPath string
+		wantPath  string
+	}{
+		{"DefaultMountPath", "", "/v1/auth/approle/login"},
+		{"CustomMountPath", "ci-approle", "/v1/auth/ci-approle/login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotBody map[string]string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				w.Write([]byte(`{"auth":{"client_token":"s.approle","policies":["ci"],"lease_duration":1200,"renewable":true}}`))
+			})
+			a := c.AppRole()
+			if tt.mountPath != "" {
+				a = a.WithMountPath(tt.mountPath)
+			}
+			got, err := a.Login("role", "secret")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			wantBody := map[string]string{"role_id": "role", "secret_id": "secret"}
+			if gotPath != tt.wantPath || !reflect.DeepEqual(gotBody, wantBody) {
+				t.Errorf("Login() request = %s %v, want %s %v", gotPath, gotBody, tt.wantPath, wantBody)
+			}
+			if got.ClientToken != "s.approle" || c.Token != "s.approle" {
+				t.Errorf("Login() token = %q, client token = %q, want %q", got.ClientToken, c.Token, "s.approle")
+			}
+		})
+	}
+}
+
+func Test_appRoleImpl_GenerateSecretID(t *testing.T) {
+	var gotBody map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"data":{"secret_id":"sid","secret_id_accessor":"acc","secret_id_ttl":600,"secret_id_num_uses":1}}`))
+	})
+	got, err := c.AppRole().GenerateSecretID("ci", &AppRoleSecretIDOptions{
+		Metadata: map[string]string{"host": "runner-1"},
+		NumUses:  1,
+	})
+	if err != nil {
+		t.Fatalf("GenerateSecretID() error = %v", err)
+	}
+	wantBody := map[string]interface{}{"metadata": `{"host":"runner-1"}`, "num_uses": float64(1)}
+	if !reflect.DeepEqual(gotBody, wantBody) {
+		t.Errorf("GenerateSecretID() body = %v, want %v", gotBody, wantBody)
+	}
+	want := &AppRoleSecretID{SecretID: "sid", SecretIDAccessor: "acc", SecretIDTTL: 600, SecretIDNumUses: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateSecretID() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_appRoleImpl_Roles(t *testing.T) {
+	bound, unbound := true, false
+	runRequestTests(t, []requestTest{
+		{
+			name: "CreateOrUpdateRole",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AppRole().CreateOrUpdateRole("ci", &AppRoleRole{
+					BindSecretID:    &unbound,
+					SecretIDTTL:     600,
+					TokenPolicies:   []string{"ci"},
+					TokenBoundCIDRs: []string{"10.0.0.0/8"},
+				})
+			},
+			wantReq: "POST /v1/auth/approle/role/ci",
+			wantBody: map[string]interface{}{
+				"bind_secret_id":    false,
+				"secret_id_ttl":     float64(600),
+				"token_policies":    []interface{}{"ci"},
+				"token_bound_cidrs": []interface{}{"10.0.0.0/8"},
+			},
+		},
+		{
+			name: "ReadRole",
+			call: func(c *Client) (interface{}, error) { return c.AppRole().ReadRole("ci") },
+			response: `{"data":{"bind_secret_id":true,"local_secret_ids":false,"secret_id_bound_cidrs":null,` +
+				`"secret_id_num_uses":0,"secret_id_ttl":600,"token_policies":["ci"],"token_ttl":1200,` +
+				`"token_max_ttl":0,"token_num_uses":0,"token_period":0,"token_type":"default"}}`,
+			wantReq: "GET /v1/auth/approle/role/ci",
+			want: &AppRoleRole{
+				BindSecretID:  &bound,
+				SecretIDTTL:   600,
+				TokenPolicies: []string{"ci"},
+				TokenTTL:      1200,
+				TokenType:     "default",
+			},
+		},
+		{
+			name:      "ListRoles",
+			call:      func(c *Client) (interface{}, error) { return c.AppRole().ListRoles() },
+			response:  `{"data":{"keys":["ci","deploy"]}}`,
+			wantReq:   "GET /v1/auth/approle/role",
+			wantQuery: "list=true",
+			want:      []string{"ci", "deploy"},
+		},
+		{
+			name:    "DeleteRole",
+			call:    func(c *Client) (interface{}, error) { return nil, c.AppRole().DeleteRole("ci") },
+			wantReq: "DELETE /v1/auth/approle/role/ci",
+		},
+		{
+			name:     "ReadRoleID",
+			call:     func(c *Client) (interface{}, error) { return c.AppRole().ReadRoleID("ci") },
+			response: `{"data":{"role_id":"8f1ae2c6-0f0b-4a52-bd3b-8f6cdc6e6e4f"}}`,
+			wantReq:  "GET /v1/auth/approle/role/ci/role-id",
+			want:     "8f1ae2c6-0f0b-4a52-bd3b-8f6cdc6e6e4f",
+		},
+		{
+			name: "LookupSecretID",
+			call: func(c *Client) (interface{}, error) { return c.AppRole().LookupSecretID("ci", "sid") },
+			response: `{"data":{"cidr_list":["10.0.0.0/8"],"creation_time":"2021-01-01T00:00:00Z",` +
+				`"expiration_time":"2021-01-01T00:10:00Z","last_updated_time":"2021-01-01T00:00:00Z",` +
+				`"metadata":{"host":"runner-1"},"secret_id_accessor":"acc","secret_id_num_uses":1,` +
+				`"secret_id_ttl":600,"token_bound_cidrs":[]}}`,
+			wantReq:  "POST /v1/auth/approle/role/ci/secret-id/lookup",
+			wantBody: map[string]interface{}{"secret_id": "sid"},
+			want: &AppRoleSecretIDInfo{
+				CIDRList:         []string{"10.0.0.0/8"},
+				CreationTime:     "2021-01-01T00:00:00Z",
+				ExpirationTime:   "2021-01-01T00:10:00Z",
+				LastUpdatedTime:  "2021-01-01T00:00:00Z",
+				Metadata:         map[string]string{"host": "runner-1"},
+				SecretIDAccessor: "acc",
+				SecretIDNumUses:  1,
+				SecretIDTTL:      600,
+				TokenBoundCIDRs:  []string{},
+			},
+		},
+		{
+			name:     "DestroySecretID",
+			call:     func(c *Client) (interface{}, error) { return nil, c.AppRole().DestroySecretID("ci", "sid") },
+			wantReq:  "POST /v1/auth/approle/role/ci/secret-id/destroy",
+			wantBody: map[string]interface{}{"secret_id": "sid"},
+		},
+		{
+			name: "CustomMountPath",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.AppRole().WithMountPath("ci-approle").DestroySecretID("ci", "sid")
+			},
+			wantReq:  "POST /v1/auth/ci-approle/role/ci/secret-id/destroy",
+			wantBody: map[string]interface{}{"secret_id": "sid"},
+		},
+	})
+}
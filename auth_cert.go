@@ -0,0 +1,169 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+)
+
+const DefaultCertMountPath = "cert"
+
+type (
+	CertAuth interface {
+		WithMountPath(path string) CertAuth
+		Login(name string) (*AuthResult, error)
+		LoginCtx(ctx context.Context, name string) (*AuthResult, error)
+		CreateOrUpdateRole(name string, role *CertRole) error
+		CreateOrUpdateRoleCtx(ctx context.Context, name string, role *CertRole) error
+		ReadRole(name string) (*CertRole, error)
+		ReadRoleCtx(ctx context.Context, name string) (*CertRole, error)
+		ListRoles() ([]string, error)
+		ListRolesCtx(ctx context.Context) ([]string, error)
+		DeleteRole(name string) error
+		DeleteRoleCtx(ctx context.Context, name string) error
+	}
+
+	certAuthImpl struct {
+		client    *Client
+		MountPath string
+	}
+
+	// CertRole is a trusted certificate role of the cert auth method. Certificate is the PEM-encoded CA
+	// certificate that client certificates must chain to. Durations are in seconds.
+	CertRole struct {
+		Certificate                string   `json:"certificate,omitempty"`
+		DisplayName                string   `json:"display_name,omitempty"`
+		AllowedCommonNames         []string `json:"allowed_common_names,omitempty"`
+		AllowedDNSSANs             []string `json:"allowed_dns_sans,omitempty"`
+		AllowedEmailSANs           []string `json:"allowed_email_sans,omitempty"`
+		AllowedURISANs             []string `json:"allowed_uri_sans,omitempty"`
+		AllowedOrganizationalUnits []string `json:"allowed_organizational_units,omitempty"`
+		TokenPolicies              []string `json:"token_policies,omitempty"`
+		TokenTTL                   int      `json:"token_ttl,omitempty"`
+		TokenMaxTTL                int      `json:"token_max_ttl,omitempty"`
+		TokenBoundCIDRs            []string `json:"token_bound_cidrs,omitempty"`
+		TokenPeriod                int      `json:"token_period,omitempty"`
+		TokenType                  string   `json:"token_type,omitempty"`
+	}
+
+	// CertLogin is an AuthMethod that logs in with the client certificate configured through Client.SetTLSConfig
+	// or Config. Name optionally selects the certificate role. MountPath defaults to DefaultCertMountPath.
+	CertLogin struct {
+		MountPath string
+		Name      string
+	}
+)
+
+func (c *Client) CertAuth() CertAuth {
+	return &certAuthImpl{
+		client:    c,
+		MountPath: DefaultCertMountPath,
+	}
+}
+
+func (l *CertLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	a := c.CertAuth()
+	if l.MountPath != "" {
+		a = a.WithMountPath(l.MountPath)
+	}
+	return a.LoginCtx(ctx, l.Name)
+}
+
+func (a *certAuthImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return a.client.doV1(ctx, method, path.Join("auth", a.MountPath, endpoint), params, body)
+}
+
+func (a *certAuthImpl) WithMountPath(path string) CertAuth {
+	aCopy := *a
+	aCopy.MountPath = path
+	a.client.Logger.Debug("using mount path: " + path)
+	return &aCopy
+}
+
+// Login authenticates with the client certificate presented during the TLS handshake and sets the resulting token
+// as the client's token. When name is empty, Vault tries every role the certificate matches.
+// vault command: `vault login -method=cert -client-cert=cert.pem -client-key=key.pem name={name}`
+func (a *certAuthImpl) Login(name string) (*AuthResult, error) {
+	return a.LoginCtx(context.Background(), name)
+}
+
+func (a *certAuthImpl) LoginCtx(ctx context.Context, name string) (*AuthResult, error) {
+	if !a.client.hasClientCertificate() {
+		return nil, errors.New("no client certificate configured, see Client.SetTLSConfig")
+	}
+	body := map[string]interface{}{}
+	if name != "" {
+		body["name"] = name
+	}
+	r, err := a.do(withLogin(ctx), http.MethodPost, "login", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := parseAuth(r)
+	if err != nil {
+		return nil, err
+	}
+	a.client.SetToken(auth.ClientToken)
+	return auth, nil
+}
+
+// vault command: `vault write auth/cert/certs/{name} certificate=@ca.pem token_policies={policies}`
+func (a *certAuthImpl) CreateOrUpdateRole(name string, role *CertRole) error {
+	return a.CreateOrUpdateRoleCtx(context.Background(), name, role)
+}
+
+func (a *certAuthImpl) CreateOrUpdateRoleCtx(ctx context.Context, name string, role *CertRole) error {
+	r, err := a.do(ctx, http.MethodPost, "certs/"+name, nil, role)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	a.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/cert/certs/{name}`
+func (a *certAuthImpl) ReadRole(name string) (*CertRole, error) {
+	return a.ReadRoleCtx(context.Background(), name)
+}
+
+func (a *certAuthImpl) ReadRoleCtx(ctx context.Context, name string) (*CertRole, error) {
+	r, err := a.do(ctx, http.MethodGet, "certs/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.client.Logger.Trace(r)
+	v := new(CertRole)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault list auth/cert/certs`
+func (a *certAuthImpl) ListRoles() ([]string, error) {
+	return a.ListRolesCtx(context.Background())
+}
+
+func (a *certAuthImpl) ListRolesCtx(ctx context.Context) ([]string, error) {
+	r, err := a.do(ctx, http.MethodGet, "certs", newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	a.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault delete auth/cert/certs/{name}`
+func (a *certAuthImpl) DeleteRole(name string) error {
+	return a.DeleteRoleCtx(context.Background(), name)
+}
+
+func (a *certAuthImpl) DeleteRoleCtx(ctx context.Context, name string) error {
+	r, err := a.do(ctx, http.MethodDelete, "certs/"+name, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	a.client.Logger.Trace(r)
+	return nil
+}
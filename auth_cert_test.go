@@ -0,0 +1,116 @@
+package govault
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func Test_certAuthImpl_Login(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, _ := writeTestCert(t, dir, "host-1")
+
+	var gotPeer string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/cert/login" || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotPeer = r.TLS.PeerCertificates[0].Subject.CommonName
+		w.Write([]byte(`{"auth":{"client_token":"s.cert"}}`))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	t.Run("WithoutClientCertificate", func(t *testing.T) {
+		c := NewClient(&http.Client{}, srv.URL, "", NewDiscardLogger())
+		if _, err := c.CertAuth().Login("web"); err == nil {
+			t.Errorf("Login() error = nil, want error")
+		}
+	})
+
+	t.Run("WithClientCertificate", func(t *testing.T) {
+		c := NewClient(&http.Client{}, srv.URL, "", NewDiscardLogger())
+		if err := c.SetTLSConfig(&TLSConfig{CACertBytes: serverCA, ClientCert: certFile, ClientKey: keyFile}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.CertAuth().Login("web")
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if got.ClientToken != "s.cert" || c.Token != "s.cert" || gotPeer != "host-1" {
+			t.Errorf("Login() token = %q, client token = %q, peer = %q", got.ClientToken, c.Token, gotPeer)
+		}
+	})
+}
+
+func Test_certAuthImpl_Roles(t *testing.T) {
+	runRequestTests(t, []requestTest{
+		{
+			name: "CreateOrUpdateRole",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.CertAuth().CreateOrUpdateRole("web", &CertRole{
+					Certificate:        "-----BEGIN CERTIFICATE-----",
+					AllowedCommonNames: []string{"*.example.com"},
+					TokenPolicies:      []string{"web"},
+					TokenTTL:           3600,
+				})
+			},
+			wantReq: "POST /v1/auth/cert/certs/web",
+			wantBody: map[string]interface{}{
+				"certificate":          "-----BEGIN CERTIFICATE-----",
+				"allowed_common_names": []interface{}{"*.example.com"},
+				"token_policies":       []interface{}{"web"},
+				"token_ttl":            float64(3600),
+			},
+		},
+		{
+			name: "ReadRole",
+			call: func(c *Client) (interface{}, error) { return c.CertAuth().ReadRole("web") },
+			response: `{"data":{"certificate":"-----BEGIN CERTIFICATE-----","display_name":"web",` +
+				`"allowed_common_names":["*.example.com"],"allowed_dns_sans":[],"token_policies":["web"],` +
+				`"token_ttl":3600,"token_max_ttl":0,"token_bound_cidrs":["10.0.0.0/8"],"token_type":"default"}}`,
+			wantReq: "GET /v1/auth/cert/certs/web",
+			want: &CertRole{
+				Certificate:        "-----BEGIN CERTIFICATE-----",
+				DisplayName:        "web",
+				AllowedCommonNames: []string{"*.example.com"},
+				AllowedDNSSANs:     []string{},
+				TokenPolicies:      []string{"web"},
+				TokenTTL:           3600,
+				TokenBoundCIDRs:    []string{"10.0.0.0/8"},
+				TokenType:          "default",
+			},
+		},
+		{
+			name:      "ListRoles",
+			call:      func(c *Client) (interface{}, error) { return c.CertAuth().ListRoles() },
+			response:  `{"data":{"keys":["db","web"]}}`,
+			wantReq:   "GET /v1/auth/cert/certs",
+			wantQuery: "list=true",
+			want:      []string{"db", "web"},
+		},
+		{
+			name:    "DeleteRole",
+			call:    func(c *Client) (interface{}, error) { return nil, c.CertAuth().DeleteRole("web") },
+			wantReq: "DELETE /v1/auth/cert/certs/web",
+		},
+		{
+			name: "CustomMountPath",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.CertAuth().WithMountPath("mtls").DeleteRole("web")
+			},
+			wantReq: "DELETE /v1/auth/mtls/certs/web",
+		},
+	})
+}
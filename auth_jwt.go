@@ -0,0 +1,292 @@
+package govault
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path"
+	"runtime"
+)
+
+const (
+	DefaultJWTMountPath       = "jwt"
+	DefaultOIDCListenAddress  = "localhost:8250"
+	DefaultOIDCCallbackPath   = "/oidc/callback"
+	oidcCallbackSuccessPage   = "<html><body>Vault login successful, you can close this window.</body></html>"
+	oidcCallbackFailurePage   = "<html><body>Vault login failed: %s</body></html>"
+	oidcClientNonceRandomSize = 20
+)
+
+type (
+	JWTAuth interface {
+		WithMountPath(path string) JWTAuth
+		Login(role, jwt string) (*AuthResult, error)
+		LoginCtx(ctx context.Context, role, jwt string) (*AuthResult, error)
+		OIDCLogin(role string, options *OIDCLoginOptions) (*AuthResult, error)
+		OIDCLoginCtx(ctx context.Context, role string, options *OIDCLoginOptions) (*AuthResult, error)
+	}
+
+	jwtAuthImpl struct {
+		client    *Client
+		MountPath string
+	}
+
+	// OIDCLoginOptions configure the browser-based OIDC login flow. The redirect URI built from ListenAddress and
+	// CallbackPath, ie http://localhost:8250/oidc/callback, must be one of the role's allowed_redirect_uris.
+	OIDCLoginOptions struct {
+		// ListenAddress is the loopback address the callback listener binds to. An empty host, as in ":8250",
+		// means localhost; other hosts must be loopback IPs. Defaults to DefaultOIDCListenAddress.
+		ListenAddress string
+
+		// CallbackPath is the path of the callback. Defaults to DefaultOIDCCallbackPath.
+		CallbackPath string
+
+		// OpenBrowser is called with the provider's authorization URL. Defaults to opening the system browser.
+		OpenBrowser func(authURL string) error
+	}
+
+	// JWTLogin is an AuthMethod that logs in with a JWT. MountPath defaults to DefaultJWTMountPath.
+	JWTLogin struct {
+		MountPath string
+		Role      string
+		JWT       string
+	}
+
+	oidcCallback struct {
+		code  string
+		state string
+		err   error
+	}
+)
+
+func (c *Client) JWTAuth() JWTAuth {
+	return &jwtAuthImpl{
+		client:    c,
+		MountPath: DefaultJWTMountPath,
+	}
+}
+
+func (l *JWTLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	j := c.JWTAuth()
+	if l.MountPath != "" {
+		j = j.WithMountPath(l.MountPath)
+	}
+	return j.LoginCtx(ctx, l.Role, l.JWT)
+}
+
+func (j *jwtAuthImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return j.client.doV1(ctx, method, path.Join("auth", j.MountPath, endpoint), params, body)
+}
+
+func (j *jwtAuthImpl) WithMountPath(path string) JWTAuth {
+	jCopy := *j
+	jCopy.MountPath = path
+	j.client.Logger.Debug("using mount path: " + path)
+	return &jCopy
+}
+
+// Login exchanges a JWT for a token, which is also set as the client's token.
+// vault command: `vault write auth/jwt/login role={role} jwt={jwt}`
+func (j *jwtAuthImpl) Login(role, jwt string) (*AuthResult, error) {
+	return j.LoginCtx(context.Background(), role, jwt)
+}
+
+func (j *jwtAuthImpl) LoginCtx(ctx context.Context, role, jwt string) (*AuthResult, error) {
+	body := map[string]interface{}{"role": role, "jwt": jwt}
+	r, err := j.do(withLogin(ctx), http.MethodPost, "login", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return j.setAuth(r)
+}
+
+// OIDCLogin runs the browser-based OIDC flow: it starts a loopback callback listener, asks Vault for the provider's
+// authorization URL, opens it in the browser, verifies the state of the provider's callback and exchanges the
+// authorization code for a token, which is also set as the client's token. It blocks until the callback arrives or
+// ctx is done.
+// vault command: `vault login -method=oidc role={role}`
+func (j *jwtAuthImpl) OIDCLogin(role string, options *OIDCLoginOptions) (*AuthResult, error) {
+	return j.OIDCLoginCtx(context.Background(), role, options)
+}
+
+func (j *jwtAuthImpl) OIDCLoginCtx(ctx context.Context, role string, options *OIDCLoginOptions) (*AuthResult, error) {
+	ctx = withLogin(ctx)
+	opts := OIDCLoginOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.ListenAddress == "" {
+		opts.ListenAddress = DefaultOIDCListenAddress
+	}
+	if opts.CallbackPath == "" {
+		opts.CallbackPath = DefaultOIDCCallbackPath
+	}
+	if opts.OpenBrowser == nil {
+		opts.OpenBrowser = openBrowser
+	}
+
+	clientNonce, err := randomString(oidcClientNonceRandomSize)
+	if err != nil {
+		return nil, err
+	}
+
+	host, listenAddress, err := oidcListenAddress(opts.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// listen before requesting the auth URL so that the redirect URI has the actual port
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("starting OIDC callback listener: %w", err)
+	}
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	redirectURI := "http://" + net.JoinHostPort(host, port) + opts.CallbackPath
+
+	// ask Vault for the provider's authorization URL
+	body := map[string]interface{}{
+		"role":         role,
+		"redirect_uri": redirectURI,
+		"client_nonce": clientNonce,
+	}
+	r, err := j.do(ctx, http.MethodPost, "oidc/auth_url", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	data := new(struct {
+		AuthURL string `json:"auth_url"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return nil, err
+	}
+	if data.AuthURL == "" {
+		return nil, fmt.Errorf("no OIDC auth URL returned, check that %s is an allowed redirect URI of role %q", redirectURI, role)
+	}
+	authURL, err := url.Parse(data.AuthURL)
+	if err != nil {
+		return nil, err
+	}
+	state := authURL.Query().Get("state")
+	if state == "" {
+		return nil, errors.New("OIDC auth URL has no state parameter")
+	}
+
+	// wait for the provider to redirect the browser back to the listener
+	callbackCh := make(chan oidcCallback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(opts.CallbackPath, oidcCallbackHandler(state, callbackCh))
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	if err := opts.OpenBrowser(data.AuthURL); err != nil {
+		return nil, fmt.Errorf("opening browser: %w", err)
+	}
+
+	var cb oidcCallback
+	select {
+	case <-ctx.Done():
+		return nil, &ErrCanceled{Err: ctx.Err()}
+	case cb = <-callbackCh:
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	// exchange the authorization code for a token
+	q := newQuery().Set("state", cb.state).Set("code", cb.code).Set("client_nonce", clientNonce)
+	r, err = j.do(ctx, http.MethodGet, "oidc/callback", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	return j.setAuth(r)
+}
+
+func (j *jwtAuthImpl) setAuth(r *vaultResponse) (*AuthResult, error) {
+	auth, err := parseAuth(r)
+	if err != nil {
+		return nil, err
+	}
+	j.client.SetToken(auth.ClientToken)
+	return auth, nil
+}
+
+// oidcCallbackHandler serves the provider's redirect back to the callback listener, sending the parsed callback on
+// callbackCh.
+// oidcListenAddress returns the host of the redirect URI and the address to listen on for addr, defaulting an empty
+// host to localhost so the listener never binds to all interfaces, and rejecting hosts that are not loopback.
+func oidcListenAddress(addr string) (host, listenAddress string, err error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid OIDC listen address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return "", "", fmt.Errorf("OIDC listen address %q is not a loopback address", addr)
+	}
+	return host, net.JoinHostPort(host, port), nil
+}
+
+func oidcCallbackHandler(state string, callbackCh chan<- oidcCallback) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cb := parseOIDCCallback(req, state)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if cb.err != nil {
+			// the error comes from the query string, which any page can set
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, oidcCallbackFailurePage, html.EscapeString(cb.err.Error()))
+		} else {
+			w.Write([]byte(oidcCallbackSuccessPage))
+		}
+		select {
+		case callbackCh <- cb:
+		default:
+		}
+	}
+}
+
+// parseOIDCCallback extracts the authorization code from the provider's callback, verifying that its state matches
+// the one Vault issued.
+func parseOIDCCallback(req *http.Request, state string) oidcCallback {
+	q := req.URL.Query()
+	if e := q.Get("error"); e != "" {
+		return oidcCallback{err: fmt.Errorf("OIDC provider error: %s: %s", e, q.Get("error_description"))}
+	}
+	if q.Get("state") != state {
+		return oidcCallback{err: errors.New("OIDC callback state does not match")}
+	}
+	if q.Get("code") == "" {
+		return oidcCallback{err: errors.New("OIDC callback has no authorization code")}
+	}
+	return oidcCallback{code: q.Get("code"), state: q.Get("state")}
+}
+
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// openBrowser opens u in the system browser.
+func openBrowser(u string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", u).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", u).Start()
+	default:
+		return exec.Command("xdg-open", u).Start()
+	}
+}
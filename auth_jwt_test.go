@@ -0,0 +1,157 @@
+package govault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_jwtAuthImpl_OIDCLogin(t *testing.T) {
+	tests := []struct {
+		name          string
+		callbackState string
+		wantErr       bool
+	}{
+		{"Success", "vault-state", false},
+		{"StateMismatch", "forged-state", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clientNonce string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/auth/oidc/oidc/auth_url":
+					var body map[string]string
+					json.NewDecoder(r.Body).Decode(&body)
+					clientNonce = body["client_nonce"]
+					authURL := "https://idp.example.com/authorize?state=vault-state&nonce=n&redirect_uri=" + url.QueryEscape(body["redirect_uri"])
+					fmt.Fprintf(w, `{"data":{"auth_url":%q}}`, authURL)
+				case "/v1/auth/oidc/oidc/callback":
+					q := r.URL.Query()
+					if q.Get("state") != "vault-state" || q.Get("code") != "auth-code" || q.Get("client_nonce") != clientNonce {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					w.Write([]byte(`{"auth":{"client_token":"s.oidc"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			// stand in for the browser and identity provider by calling the redirect URI directly
+			openBrowser := func(authURL string) error {
+				u, err := url.Parse(authURL)
+				if err != nil {
+					return err
+				}
+				callback := u.Query().Get("redirect_uri") + "?state=" + tt.callbackState + "&code=auth-code"
+				go func() {
+					if resp, err := http.Get(callback); err == nil {
+						resp.Body.Close()
+					}
+				}()
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			got, err := c.JWTAuth().WithMountPath("oidc").OIDCLoginCtx(ctx, "dev", &OIDCLoginOptions{
+				ListenAddress: "127.0.0.1:0",
+				OpenBrowser:   openBrowser,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OIDCLogin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got.ClientToken != "s.oidc" || c.Token != "s.oidc") {
+				t.Errorf("OIDCLogin() token = %q, client token = %q, want %q", got.ClientToken, c.Token, "s.oidc")
+			}
+			if clientNonce == "" {
+				t.Errorf("OIDCLogin() sent no client nonce")
+			}
+		})
+	}
+}
+
+func Test_oidcListenAddress(t *testing.T) {
+	tests := []struct {
+		addr           string
+		wantHost       string
+		wantListenAddr string
+		wantErr        bool
+	}{
+		{addr: "localhost:8250", wantHost: "localhost", wantListenAddr: "localhost:8250"},
+		{addr: ":8250", wantHost: "localhost", wantListenAddr: "localhost:8250"},
+		{addr: "127.0.0.1:0", wantHost: "127.0.0.1", wantListenAddr: "127.0.0.1:0"},
+		{addr: "[::1]:8250", wantHost: "::1", wantListenAddr: "[::1]:8250"},
+		{addr: "0.0.0.0:8250", wantErr: true},
+		{addr: "192.168.1.10:8250", wantErr: true},
+		{addr: "example.com:8250", wantErr: true},
+		{addr: "localhost", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			host, listenAddr, err := oidcListenAddress(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("oidcListenAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if host != tt.wantHost || listenAddr != tt.wantListenAddr {
+				t.Errorf("oidcListenAddress() = %q, %q, want %q, %q", host, listenAddr, tt.wantHost, tt.wantListenAddr)
+			}
+		})
+	}
+}
+
+func Test_oidcCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+		notInBody  string
+	}{
+		{"Success", "state=s1&code=abc", http.StatusOK, "Vault login successful", ""},
+		{
+			name:       "EscapesProviderError",
+			query:      "error=" + url.QueryEscape("<script>alert(1)</script>") + "&error_description=" + url.QueryEscape(`"><img>`),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "&lt;script&gt;alert(1)&lt;/script&gt;",
+			notInBody:  "<script>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbackCh := make(chan oidcCallback, 1)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/oidc/callback?"+tt.query, nil)
+			oidcCallbackHandler("s1", callbackCh)(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+			if tt.notInBody != "" && strings.Contains(body, tt.notInBody) {
+				t.Errorf("body = %q, must not contain %q", body, tt.notInBody)
+			}
+			if strings.Contains(body, "<img>") {
+				t.Errorf("body = %q contains unescaped markup", body)
+			}
+			select {
+			case <-callbackCh:
+			default:
+				t.Error("no callback sent")
+			}
+		})
+	}
+}
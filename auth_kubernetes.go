@@ -0,0 +1,105 @@
+package govault
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	DefaultKubernetesMountPath = "kubernetes"
+	DefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+type (
+	KubernetesAuth interface {
+		WithMountPath(path string) KubernetesAuth
+		WithTokenPath(path string) KubernetesAuth
+		Login(role string) (*AuthResult, error)
+		LoginCtx(ctx context.Context, role string) (*AuthResult, error)
+	}
+
+	kubernetesAuthImpl struct {
+		client    *Client
+		MountPath string
+		TokenPath string
+	}
+
+	// KubernetesLogin is an AuthMethod that logs in with the pod's service account token. MountPath defaults to
+	// DefaultKubernetesMountPath and TokenPath to DefaultKubernetesTokenPath.
+	KubernetesLogin struct {
+		MountPath string
+		TokenPath string
+		Role      string
+	}
+)
+
+func (c *Client) KubernetesAuth() KubernetesAuth {
+	return &kubernetesAuthImpl{
+		client:    c,
+		MountPath: DefaultKubernetesMountPath,
+		TokenPath: DefaultKubernetesTokenPath,
+	}
+}
+
+func (k *kubernetesAuthImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return k.client.doV1(ctx, method, path.Join("auth", k.MountPath, endpoint), params, body)
+}
+
+func (k *kubernetesAuthImpl) WithMountPath(path string) KubernetesAuth {
+	kCopy := *k
+	kCopy.MountPath = path
+	k.client.Logger.Debug("using mount path: " + path)
+	return &kCopy
+}
+
+// WithTokenPath sets the file the service account JWT is read from, ie a projected service account token volume.
+func (k *kubernetesAuthImpl) WithTokenPath(path string) KubernetesAuth {
+	kCopy := *k
+	kCopy.TokenPath = path
+	k.client.Logger.Debug("using service account token path: " + path)
+	return &kCopy
+}
+
+// Login reads the service account JWT from the token path and exchanges it for a token, which is also set as the
+// client's token. The JWT is read on every call, so rotated projected tokens are picked up on re-login.
+// vault command: `vault write auth/kubernetes/login role={role} jwt=@/var/run/secrets/kubernetes.io/serviceaccount/token`
+func (k *kubernetesAuthImpl) Login(role string) (*AuthResult, error) {
+	return k.LoginCtx(context.Background(), role)
+}
+
+func (k *kubernetesAuthImpl) LoginCtx(ctx context.Context, role string) (*AuthResult, error) {
+	ctx = withLogin(ctx)
+	jwt, err := ioutil.ReadFile(k.TokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading service account token: %w", err)
+	}
+	body := map[string]interface{}{
+		"role": role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}
+	r, err := k.do(ctx, http.MethodPost, "login", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := parseAuth(r)
+	if err != nil {
+		return nil, err
+	}
+	k.client.SetToken(auth.ClientToken)
+	return auth, nil
+}
+
+func (l *KubernetesLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	k := c.KubernetesAuth()
+	if l.MountPath != "" {
+		k = k.WithMountPath(l.MountPath)
+	}
+	if l.TokenPath != "" {
+		k = k.WithTokenPath(l.TokenPath)
+	}
+	return k.LoginCtx(ctx, l.Role)
+}
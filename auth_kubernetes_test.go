@@ -0,0 +1,49 @@
+package govault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_kubernetesAuthImpl_Login(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenPath := filepath.Join(dir, "token")
+
+	var gotPath string
+	var gotBody map[string]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Write([]byte(`{"auth":{"client_token":"s.k8s-` + gotBody["jwt"] + `"}}`))
+	})
+	k := c.KubernetesAuth().WithMountPath("k8s-prod").WithTokenPath(tokenPath)
+
+	// the JWT is re-read on every login so that rotated tokens are used
+	for _, jwt := range []string{"first", "second"} {
+		if err := ioutil.WriteFile(tokenPath, []byte(jwt+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := k.Login("app")
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if gotPath != "/v1/auth/k8s-prod/login" || gotBody["role"] != "app" || gotBody["jwt"] != jwt {
+			t.Errorf("Login() request = %s %v, want role %q and jwt %q", gotPath, gotBody, "app", jwt)
+		}
+		if got.ClientToken != "s.k8s-"+jwt || c.Token != got.ClientToken {
+			t.Errorf("Login() token = %q, client token = %q", got.ClientToken, c.Token)
+		}
+	}
+
+	if _, err := c.KubernetesAuth().WithTokenPath(filepath.Join(dir, "missing")).Login("app"); err == nil {
+		t.Errorf("Login() with missing token file error = nil, want error")
+	}
+}
@@ -0,0 +1,243 @@
+package govault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const DefaultLDAPMountPath = "ldap"
+
+type (
+	LDAP interface {
+		WithMountPath(path string) LDAP
+		Login(username, password string, options *LoginOptions) (*AuthResult, error)
+		LoginCtx(ctx context.Context, username, password string, options *LoginOptions) (*AuthResult, error)
+		CreateOrUpdateGroup(name string, group *LDAPGroup) error
+		CreateOrUpdateGroupCtx(ctx context.Context, name string, group *LDAPGroup) error
+		ReadGroup(name string) (*LDAPGroup, error)
+		ReadGroupCtx(ctx context.Context, name string) (*LDAPGroup, error)
+		ListGroups() ([]string, error)
+		ListGroupsCtx(ctx context.Context) ([]string, error)
+		DeleteGroup(name string) error
+		DeleteGroupCtx(ctx context.Context, name string) error
+		CreateOrUpdateUser(username string, user *LDAPUser) error
+		CreateOrUpdateUserCtx(ctx context.Context, username string, user *LDAPUser) error
+		ReadUser(username string) (*LDAPUser, error)
+		ReadUserCtx(ctx context.Context, username string) (*LDAPUser, error)
+		ListUsers() ([]string, error)
+		ListUsersCtx(ctx context.Context) ([]string, error)
+		DeleteUser(username string) error
+		DeleteUserCtx(ctx context.Context, username string) error
+	}
+
+	ldapImpl struct {
+		client    *Client
+		MountPath string
+	}
+
+	// LDAPGroup maps an LDAP group to Vault policies.
+	LDAPGroup struct {
+		Policies []string `json:"policies"`
+	}
+
+	// LDAPUser maps an LDAP user to Vault policies and to LDAP groups in addition to those found in LDAP.
+	LDAPUser struct {
+		Policies []string `json:"policies"`
+		Groups   []string `json:"groups"`
+	}
+
+	// commaStringSlice decodes a list that Vault returns either as a JSON array or as a comma-separated string, ie
+	// the groups of an LDAP user.
+	commaStringSlice []string
+
+	// LDAPLogin is an AuthMethod that logs in with an LDAP username and password. MountPath defaults to
+	// DefaultLDAPMountPath.
+	LDAPLogin struct {
+		MountPath string
+		Username  string
+		Password  string
+		Options   *LoginOptions
+	}
+)
+
+func (c *Client) LDAP() LDAP {
+	return &ldapImpl{
+		client:    c,
+		MountPath: DefaultLDAPMountPath,
+	}
+}
+
+func (l *LDAPLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	a := c.LDAP()
+	if l.MountPath != "" {
+		a = a.WithMountPath(l.MountPath)
+	}
+	return a.LoginCtx(ctx, l.Username, l.Password, l.Options)
+}
+
+func (l *ldapImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return l.client.doV1(ctx, method, path.Join("auth", l.MountPath, endpoint), params, body)
+}
+
+func (l *ldapImpl) WithMountPath(path string) LDAP {
+	lCopy := *l
+	lCopy.MountPath = path
+	l.client.Logger.Debug("using mount path: " + path)
+	return &lCopy
+}
+
+// Login exchanges an LDAP username and password for a token, which is also set as the client's token.
+// vault command: `vault login -method=ldap username={username}`
+func (l *ldapImpl) Login(username, password string, options *LoginOptions) (*AuthResult, error) {
+	return l.LoginCtx(context.Background(), username, password, options)
+}
+
+func (l *ldapImpl) LoginCtx(ctx context.Context, username, password string, options *LoginOptions) (*AuthResult, error) {
+	return passwordLogin(ctx, l.client, l.MountPath, username, password, options)
+}
+
+// vault command: `vault write auth/ldap/groups/{name} policies={policies}`
+func (l *ldapImpl) CreateOrUpdateGroup(name string, group *LDAPGroup) error {
+	return l.CreateOrUpdateGroupCtx(context.Background(), name, group)
+}
+
+func (l *ldapImpl) CreateOrUpdateGroupCtx(ctx context.Context, name string, group *LDAPGroup) error {
+	r, err := l.do(ctx, http.MethodPost, "groups/"+name, nil, group)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	l.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/ldap/groups/{name}`
+func (l *ldapImpl) ReadGroup(name string) (*LDAPGroup, error) {
+	return l.ReadGroupCtx(context.Background(), name)
+}
+
+func (l *ldapImpl) ReadGroupCtx(ctx context.Context, name string) (*LDAPGroup, error) {
+	r, err := l.do(ctx, http.MethodGet, "groups/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	l.client.Logger.Trace(r)
+	v := new(LDAPGroup)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault list auth/ldap/groups`
+func (l *ldapImpl) ListGroups() ([]string, error) {
+	return l.ListGroupsCtx(context.Background())
+}
+
+func (l *ldapImpl) ListGroupsCtx(ctx context.Context) ([]string, error) {
+	r, err := l.do(ctx, http.MethodGet, "groups", newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	l.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault delete auth/ldap/groups/{name}`
+func (l *ldapImpl) DeleteGroup(name string) error {
+	return l.DeleteGroupCtx(context.Background(), name)
+}
+
+func (l *ldapImpl) DeleteGroupCtx(ctx context.Context, name string) error {
+	r, err := l.do(ctx, http.MethodDelete, "groups/"+name, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	l.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault write auth/ldap/users/{username} policies={policies} groups={groups}`
+func (l *ldapImpl) CreateOrUpdateUser(username string, user *LDAPUser) error {
+	return l.CreateOrUpdateUserCtx(context.Background(), username, user)
+}
+
+func (l *ldapImpl) CreateOrUpdateUserCtx(ctx context.Context, username string, user *LDAPUser) error {
+	r, err := l.do(ctx, http.MethodPost, "users/"+username, nil, user)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	l.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/ldap/users/{username}`
+func (l *ldapImpl) ReadUser(username string) (*LDAPUser, error) {
+	return l.ReadUserCtx(context.Background(), username)
+}
+
+func (l *ldapImpl) ReadUserCtx(ctx context.Context, username string) (*LDAPUser, error) {
+	r, err := l.do(ctx, http.MethodGet, "users/"+username, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	l.client.Logger.Trace(r)
+	data := new(struct {
+		Policies []string         `json:"policies"`
+		Groups   commaStringSlice `json:"groups"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return nil, err
+	}
+	return &LDAPUser{Policies: data.Policies, Groups: data.Groups}, nil
+}
+
+// vault command: `vault list auth/ldap/users`
+func (l *ldapImpl) ListUsers() ([]string, error) {
+	return l.ListUsersCtx(context.Background())
+}
+
+func (l *ldapImpl) ListUsersCtx(ctx context.Context) ([]string, error) {
+	r, err := l.do(ctx, http.MethodGet, "users", newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	l.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault delete auth/ldap/users/{username}`
+func (l *ldapImpl) DeleteUser(username string) error {
+	return l.DeleteUserCtx(context.Background(), username)
+}
+
+func (l *ldapImpl) DeleteUserCtx(ctx context.Context, username string) error {
+	r, err := l.do(ctx, http.MethodDelete, "users/"+username, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	l.client.Logger.Trace(r)
+	return nil
+}
+
+func (s *commaStringSlice) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var joined string
+	if err := json.Unmarshal(b, &joined); err != nil {
+		return err
+	}
+	*s = nil
+	for _, v := range strings.Split(joined, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
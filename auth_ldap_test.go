@@ -0,0 +1,80 @@
+package govault
+
+import "testing"
+
+func Test_ldapImpl(t *testing.T) {
+	runRequestTests(t, []requestTest{
+		{
+			name: "CreateOrUpdateGroup",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.LDAP().CreateOrUpdateGroup("engineers", &LDAPGroup{Policies: []string{"dev"}})
+			},
+			wantReq:  "POST /v1/auth/ldap/groups/engineers",
+			wantBody: map[string]interface{}{"policies": []interface{}{"dev"}},
+		},
+		{
+			name:     "ReadGroup",
+			call:     func(c *Client) (interface{}, error) { return c.LDAP().ReadGroup("engineers") },
+			response: `{"data":{"policies":["dev","ops"]}}`,
+			wantReq:  "GET /v1/auth/ldap/groups/engineers",
+			want:     &LDAPGroup{Policies: []string{"dev", "ops"}},
+		},
+		{
+			name:      "ListGroups",
+			call:      func(c *Client) (interface{}, error) { return c.LDAP().ListGroups() },
+			response:  `{"data":{"keys":["engineers","ops"]}}`,
+			wantReq:   "GET /v1/auth/ldap/groups",
+			wantQuery: "list=true",
+			want:      []string{"engineers", "ops"},
+		},
+		{
+			name:    "DeleteGroup",
+			call:    func(c *Client) (interface{}, error) { return nil, c.LDAP().DeleteGroup("engineers") },
+			wantReq: "DELETE /v1/auth/ldap/groups/engineers",
+		},
+		{
+			name: "CreateOrUpdateUser",
+			call: func(c *Client) (interface{}, error) {
+				return nil, c.LDAP().CreateOrUpdateUser("alice", &LDAPUser{Policies: []string{"dev"}, Groups: []string{"ops"}})
+			},
+			wantReq:  "POST /v1/auth/ldap/users/alice",
+			wantBody: map[string]interface{}{"policies": []interface{}{"dev"}, "groups": []interface{}{"ops"}},
+		},
+		{
+			name:     "ReadUser",
+			call:     func(c *Client) (interface{}, error) { return c.LDAP().ReadUser("alice") },
+			response: `{"data":{"policies":["dev"],"groups":"ops,admins"}}`,
+			wantReq:  "GET /v1/auth/ldap/users/alice",
+			want:     &LDAPUser{Policies: []string{"dev"}, Groups: []string{"ops", "admins"}},
+		},
+		{
+			name:     "ReadUserWithoutGroups",
+			call:     func(c *Client) (interface{}, error) { return c.LDAP().ReadUser("bob") },
+			response: `{"data":{"policies":["dev"],"groups":""}}`,
+			wantReq:  "GET /v1/auth/ldap/users/bob",
+			want:     &LDAPUser{Policies: []string{"dev"}},
+		},
+		{
+			name:      "ListUsers",
+			call:      func(c *Client) (interface{}, error) { return c.LDAP().ListUsers() },
+			response:  `{"data":{"keys":["alice","bob"]}}`,
+			wantReq:   "GET /v1/auth/ldap/users",
+			wantQuery: "list=true",
+			want:      []string{"alice", "bob"},
+		},
+		{
+			name:    "DeleteUser",
+			call:    func(c *Client) (interface{}, error) { return nil, c.LDAP().DeleteUser("alice") },
+			wantReq: "DELETE /v1/auth/ldap/users/alice",
+		},
+		{
+			name: "CustomMountPath",
+			call: func(c *Client) (interface{}, error) {
+				return c.LDAP().WithMountPath("corp-ldap").ReadGroup("engineers")
+			},
+			response: `{"data":{"policies":["dev"]}}`,
+			wantReq:  "GET /v1/auth/corp-ldap/groups/engineers",
+			want:     &LDAPGroup{Policies: []string{"dev"}},
+		},
+	})
+}
@@ -0,0 +1,170 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_reauthenticate(t *testing.T) {
+	var logins int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "fresh" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"foo":"bar"}}}`))
+	})
+	c.Token = "expired"
+	c.Auth = AuthMethodFunc(func(ctx context.Context, c *Client) (*AuthResult, error) {
+		atomic.AddInt32(&logins, 1)
+		time.Sleep(50 * time.Millisecond) // give concurrent callers time to pile up
+		return &AuthResult{ClientToken: "fresh"}, nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.KVv2().ReadSecretVersion("foo", 0)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("ReadSecretVersion() error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&logins); got != 1 {
+		t.Errorf("logins = %d, want 1", got)
+	}
+	if got := c.token(); got != "fresh" {
+		t.Errorf("token = %q, want %q", got, "fresh")
+	}
+}
+
+func TestClient_reauthenticate_LoginFails(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusForbidden)
+	})
+	loginErr := errors.New("bad credentials")
+	c.Auth = AuthMethodFunc(func(ctx context.Context, c *Client) (*AuthResult, error) {
+		return nil, loginErr
+	})
+
+	if _, err := c.KVv2().ReadSecretVersion("foo", 0); !errors.Is(err, loginErr) {
+		t.Errorf("ReadSecretVersion() error = %v, want %v", err, loginErr)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestAppRoleLogin(t *testing.T) {
+	var loginRequests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// a forbidden login must not trigger another login
+		atomic.AddInt32(&loginRequests, 1)
+		w.WriteHeader(http.StatusForbidden)
+	})
+	c.Auth = &AppRoleLogin{MountPath: "ci", RoleID: "role", SecretID: "secret"}
+
+	if _, err := c.Login(context.Background()); !errors.Is(err, &ErrForbidden{}) {
+		t.Errorf("Login() error = %v, want %v", err, &ErrForbidden{})
+	}
+	if got := atomic.LoadInt32(&loginRequests); got != 1 {
+		t.Errorf("login requests = %d, want 1", got)
+	}
+}
+
+func TestClient_reauthenticate_Namespaces(t *testing.T) {
+	var logins int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "fresh-"+r.Header.Get("X-Vault-Namespace") {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"foo":"bar"}}}`))
+	})
+	c.Token = "expired"
+	c.Auth = AuthMethodFunc(func(ctx context.Context, c *Client) (*AuthResult, error) {
+		atomic.AddInt32(&logins, 1)
+		time.Sleep(50 * time.Millisecond) // give the other namespace time to fail too
+		return &AuthResult{ClientToken: "fresh-" + c.Namespace}, nil
+	})
+
+	var wg sync.WaitGroup
+	for _, ns := range []string{"team-a", "team-b"} {
+		nsClient := c.WithNamespace(ns)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := nsClient.KVv2().ReadSecretVersion("foo", 0); err != nil {
+				t.Errorf("ReadSecretVersion() in %s error = %v", nsClient.Namespace, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&logins); got != 2 {
+		t.Errorf("logins = %d, want 2", got)
+	}
+}
+
+func TestClient_Login_CallerCanceled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	started, release := make(chan struct{}), make(chan struct{})
+	c.Auth = AuthMethodFunc(func(ctx context.Context, c *Client) (*AuthResult, error) {
+		close(started)
+		<-release
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &AuthResult{ClientToken: "fresh"}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.Login(ctx)
+		first <- err
+	}()
+	<-started
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.Login(context.Background())
+		second <- err
+	}()
+	for {
+		c.auth.mu.RLock()
+		waiters := c.auth.inflight.waiters
+		c.auth.mu.RUnlock()
+		if waiters == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Login() error = %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Login() error = %v, want the shared login to survive the first caller", err)
+	}
+	if got := c.token(); got != "fresh" {
+		t.Errorf("token = %q, want %q", got, "fresh")
+	}
+}
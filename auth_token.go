@@ -0,0 +1,189 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const tokenAuthMountPath = "auth/token"
+
+type (
+	TokenAuth interface {
+		LookupSelf() (*TokenInfo, error)
+		LookupSelfCtx(ctx context.Context) (*TokenInfo, error)
+		RenewSelf(increment time.Duration) (*AuthResult, error)
+		RenewSelfCtx(ctx context.Context, increment time.Duration) (*AuthResult, error)
+		RevokeSelf() error
+		RevokeSelfCtx(ctx context.Context) error
+		Create(options *TokenCreateOptions) (*AuthResult, error)
+		CreateCtx(ctx context.Context, options *TokenCreateOptions) (*AuthResult, error)
+		LookupAccessor(accessor string) (*TokenInfo, error)
+		LookupAccessorCtx(ctx context.Context, accessor string) (*TokenInfo, error)
+		RevokeAccessor(accessor string) error
+		RevokeAccessorCtx(ctx context.Context, accessor string) error
+	}
+
+	tokenAuthImpl struct {
+		client *Client
+	}
+
+	// TokenInfo is the data returned by a token lookup. Durations are in seconds.
+	TokenInfo struct {
+		Accessor       string            `json:"accessor"`
+		CreationTime   int64             `json:"creation_time"`
+		CreationTTL    int               `json:"creation_ttl"`
+		DisplayName    string            `json:"display_name"`
+		EntityID       string            `json:"entity_id"`
+		ExpireTime     string            `json:"expire_time"`
+		ExplicitMaxTTL int               `json:"explicit_max_ttl"`
+		ID             string            `json:"id"`
+		IssueTime      string            `json:"issue_time"`
+		Meta           map[string]string `json:"meta"`
+		NumUses        int               `json:"num_uses"`
+		Orphan         bool              `json:"orphan"`
+		Path           string            `json:"path"`
+		Period         int               `json:"period"`
+		Policies       []string          `json:"policies"`
+		Renewable      bool              `json:"renewable"`
+		Role           string            `json:"role"`
+		TTL            int               `json:"ttl"`
+		Type           string            `json:"type"`
+	}
+
+	// TokenCreateOptions are the parameters of a token create request. Durations are Vault duration strings, ie "1h".
+	// Role creates the token against a token role, and Orphan creates a token without a parent.
+	TokenCreateOptions struct {
+		Role            string            `json:"-"`
+		Orphan          bool              `json:"-"`
+		ID              string            `json:"id,omitempty"`
+		Policies        []string          `json:"policies,omitempty"`
+		Meta            map[string]string `json:"meta,omitempty"`
+		NoDefaultPolicy bool              `json:"no_default_policy,omitempty"`
+		Renewable       *bool             `json:"renewable,omitempty"`
+		TTL             string            `json:"ttl,omitempty"`
+		Type            string            `json:"type,omitempty"`
+		ExplicitMaxTTL  string            `json:"explicit_max_ttl,omitempty"`
+		DisplayName     string            `json:"display_name,omitempty"`
+		NumUses         int               `json:"num_uses,omitempty"`
+		Period          string            `json:"period,omitempty"`
+		EntityAlias     string            `json:"entity_alias,omitempty"`
+	}
+)
+
+func (c *Client) TokenAuth() TokenAuth {
+	return &tokenAuthImpl{client: c}
+}
+
+func (t *tokenAuthImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return t.client.doV1(ctx, method, tokenAuthMountPath+"/"+endpoint, params, body)
+}
+
+// vault command: `vault token lookup`
+func (t *tokenAuthImpl) LookupSelf() (*TokenInfo, error) {
+	return t.LookupSelfCtx(context.Background())
+}
+
+func (t *tokenAuthImpl) LookupSelfCtx(ctx context.Context) (*TokenInfo, error) {
+	r, err := t.do(ctx, http.MethodGet, "lookup-self", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.client.Logger.Trace(r)
+	v := new(TokenInfo)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault token renew -increment={increment}`
+func (t *tokenAuthImpl) RenewSelf(increment time.Duration) (*AuthResult, error) {
+	return t.RenewSelfCtx(context.Background(), increment)
+}
+
+func (t *tokenAuthImpl) RenewSelfCtx(ctx context.Context, increment time.Duration) (*AuthResult, error) {
+	body := map[string]interface{}{}
+	if increment > 0 {
+		body["increment"] = int(increment / time.Second)
+	}
+	r, err := t.do(ctx, http.MethodPost, "renew-self", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	t.client.Logger.Trace(r)
+	return parseAuth(r)
+}
+
+// vault command: `vault token revoke -self`
+func (t *tokenAuthImpl) RevokeSelf() error {
+	return t.RevokeSelfCtx(context.Background())
+}
+
+func (t *tokenAuthImpl) RevokeSelfCtx(ctx context.Context) error {
+	r, err := t.do(ctx, http.MethodPost, "revoke-self", nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	t.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault token create -policy={policy} -ttl={ttl} -orphan -role={role}`
+func (t *tokenAuthImpl) Create(options *TokenCreateOptions) (*AuthResult, error) {
+	return t.CreateCtx(context.Background(), options)
+}
+
+func (t *tokenAuthImpl) CreateCtx(ctx context.Context, options *TokenCreateOptions) (*AuthResult, error) {
+	if options == nil {
+		options = &TokenCreateOptions{}
+	}
+	endpoint := "create"
+	switch {
+	case options.Role != "":
+		endpoint = "create/" + options.Role
+	case options.Orphan:
+		endpoint = "create-orphan"
+	}
+	r, err := t.do(ctx, http.MethodPost, endpoint, nil, options)
+	if err != nil {
+		return nil, err
+	}
+	t.client.Logger.Trace(r)
+	return parseAuth(r)
+}
+
+// vault command: `vault token lookup -accessor {accessor}`
+func (t *tokenAuthImpl) LookupAccessor(accessor string) (*TokenInfo, error) {
+	return t.LookupAccessorCtx(context.Background(), accessor)
+}
+
+func (t *tokenAuthImpl) LookupAccessorCtx(ctx context.Context, accessor string) (*TokenInfo, error) {
+	body := map[string]interface{}{"accessor": accessor}
+	r, err := t.do(ctx, http.MethodPost, "lookup-accessor", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	t.client.Logger.Trace(r)
+	v := new(TokenInfo)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault token revoke -accessor {accessor}`
+func (t *tokenAuthImpl) RevokeAccessor(accessor string) error {
+	return t.RevokeAccessorCtx(context.Background(), accessor)
+}
+
+func (t *tokenAuthImpl) RevokeAccessorCtx(ctx context.Context, accessor string) error {
+	body := map[string]interface{}{"accessor": accessor}
+	r, err := t.do(ctx, http.MethodPost, "revoke-accessor", nil, body)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	t.client.Logger.Trace(r)
+	return nil
+}
@@ -0,0 +1,131 @@
+package govault
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_tokenAuthImpl_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *TokenCreateOptions
+		wantPath string
+		wantBody map[string]interface{}
+	}{
+		{
+			name:     "Default",
+			options:  nil,
+			wantPath: "/v1/auth/token/create",
+			wantBody: map[string]interface{}{},
+		},
+		{
+			name:     "Role",
+			options:  &TokenCreateOptions{Role: "ci", Policies: []string{"read"}},
+			wantPath: "/v1/auth/token/create/ci",
+			wantBody: map[string]interface{}{"policies": []interface{}{"read"}},
+		},
+		{
+			name:     "Orphan",
+			options:  &TokenCreateOptions{Orphan: true, TTL: "1h"},
+			wantPath: "/v1/auth/token/create-orphan",
+			wantBody: map[string]interface{}{"ttl": "1h"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotBody map[string]interface{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				w.Write([]byte(`{"auth":{"client_token":"s.new","accessor":"acc","policies":["default"],"lease_duration":3600,"renewable":true}}`))
+			})
+			got, err := c.TokenAuth().Create(tt.options)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if gotPath != tt.wantPath || !reflect.DeepEqual(gotBody, tt.wantBody) {
+				t.Errorf("Create() request = %s %v, want %s %v", gotPath, gotBody, tt.wantPath, tt.wantBody)
+			}
+			want := &AuthResult{ClientToken: "s.new", Accessor: "acc", Policies: []string{"default"}, LeaseDuration: 3600, Renewable: true}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Create() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func Test_tokenAuthImpl_LookupSelf(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/token/lookup-self" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"accessor":"acc","policies":["default"],"ttl":120,"period":3600,"renewable":true,"type":"service"}}`))
+	})
+	got, err := c.TokenAuth().LookupSelf()
+	if err != nil {
+		t.Fatalf("LookupSelf() error = %v", err)
+	}
+	want := &TokenInfo{Accessor: "acc", Policies: []string{"default"}, TTL: 120, Period: 3600, Renewable: true, Type: "service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LookupSelf() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_tokenAuthImpl_Requests(t *testing.T) {
+	runRequestTests(t, []requestTest{
+		{
+			name: "RenewSelf",
+			call: func(c *Client) (interface{}, error) {
+				return c.TokenAuth().RenewSelf(90*time.Second + 500*time.Millisecond)
+			},
+			response: `{"auth":{"client_token":"test","accessor":"acc","policies":["default"],"lease_duration":90,"renewable":true}}`,
+			wantReq:  "POST /v1/auth/token/renew-self",
+			wantBody: map[string]interface{}{"increment": float64(90)},
+			want: &AuthResult{
+				ClientToken: "test", Accessor: "acc", Policies: []string{"default"}, LeaseDuration: 90, Renewable: true,
+			},
+		},
+		{
+			name:     "RenewSelfWithoutIncrement",
+			call:     func(c *Client) (interface{}, error) { return c.TokenAuth().RenewSelf(0) },
+			response: `{"auth":{"client_token":"test","lease_duration":3600,"renewable":true}}`,
+			wantReq:  "POST /v1/auth/token/renew-self",
+			wantBody: map[string]interface{}{},
+			want:     &AuthResult{ClientToken: "test", LeaseDuration: 3600, Renewable: true},
+		},
+		{
+			name:    "RevokeSelf",
+			call:    func(c *Client) (interface{}, error) { return nil, c.TokenAuth().RevokeSelf() },
+			wantReq: "POST /v1/auth/token/revoke-self",
+		},
+		{
+			name: "LookupAccessor",
+			call: func(c *Client) (interface{}, error) { return c.TokenAuth().LookupAccessor("acc") },
+			response: `{"data":{"accessor":"acc","creation_time":1609459200,"display_name":"approle","id":"",` +
+				`"meta":{"role_name":"ci"},"policies":["ci","default"],"renewable":true,"ttl":1200,"type":"service"}}`,
+			wantReq:  "POST /v1/auth/token/lookup-accessor",
+			wantBody: map[string]interface{}{"accessor": "acc"},
+			want: &TokenInfo{
+				Accessor:     "acc",
+				CreationTime: 1609459200,
+				DisplayName:  "approle",
+				Meta:         map[string]string{"role_name": "ci"},
+				Policies:     []string{"ci", "default"},
+				Renewable:    true,
+				TTL:          1200,
+				Type:         "service",
+			},
+		},
+		{
+			name:     "RevokeAccessor",
+			call:     func(c *Client) (interface{}, error) { return nil, c.TokenAuth().RevokeAccessor("acc") },
+			wantReq:  "POST /v1/auth/token/revoke-accessor",
+			wantBody: map[string]interface{}{"accessor": "acc"},
+		},
+	})
+}
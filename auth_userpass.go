@@ -0,0 +1,209 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+)
+
+const DefaultUserpassMountPath = "userpass"
+
+type (
+	Userpass interface {
+		WithMountPath(path string) Userpass
+		Login(username, password string, options *LoginOptions) (*AuthResult, error)
+		LoginCtx(ctx context.Context, username, password string, options *LoginOptions) (*AuthResult, error)
+		CreateOrUpdateUser(username string, user *UserpassUser) error
+		CreateOrUpdateUserCtx(ctx context.Context, username string, user *UserpassUser) error
+		ReadUser(username string) (*UserpassUser, error)
+		ReadUserCtx(ctx context.Context, username string) (*UserpassUser, error)
+		ListUsers() ([]string, error)
+		ListUsersCtx(ctx context.Context) ([]string, error)
+		DeleteUser(username string) error
+		DeleteUserCtx(ctx context.Context, username string) error
+		UpdatePassword(username, password string) error
+		UpdatePasswordCtx(ctx context.Context, username, password string) error
+		UpdatePolicies(username string, policies []string) error
+		UpdatePoliciesCtx(ctx context.Context, username string, policies []string) error
+	}
+
+	userpassImpl struct {
+		client    *Client
+		MountPath string
+	}
+
+	// LoginOptions are optional parameters of a username and password login. MFA is sent as the X-Vault-MFA
+	// header, ie "my_totp:123456" for a login MFA method named "my_totp".
+	LoginOptions struct {
+		MFA string
+	}
+
+	// UserpassUser is the configuration of a userpass user. Password is only sent, never returned. Durations are
+	// in seconds.
+	UserpassUser struct {
+		Password        string   `json:"password,omitempty"`
+		TokenPolicies   []string `json:"token_policies,omitempty"`
+		TokenTTL        int      `json:"token_ttl,omitempty"`
+		TokenMaxTTL     int      `json:"token_max_ttl,omitempty"`
+		TokenBoundCIDRs []string `json:"token_bound_cidrs,omitempty"`
+		TokenNumUses    int      `json:"token_num_uses,omitempty"`
+		TokenPeriod     int      `json:"token_period,omitempty"`
+		TokenType       string   `json:"token_type,omitempty"`
+	}
+
+	// UserpassLogin is an AuthMethod that logs in with a username and password. MountPath defaults to
+	// DefaultUserpassMountPath.
+	UserpassLogin struct {
+		MountPath string
+		Username  string
+		Password  string
+		Options   *LoginOptions
+	}
+)
+
+func (c *Client) Userpass() Userpass {
+	return &userpassImpl{
+		client:    c,
+		MountPath: DefaultUserpassMountPath,
+	}
+}
+
+func (l *UserpassLogin) Login(ctx context.Context, c *Client) (*AuthResult, error) {
+	u := c.Userpass()
+	if l.MountPath != "" {
+		u = u.WithMountPath(l.MountPath)
+	}
+	return u.LoginCtx(ctx, l.Username, l.Password, l.Options)
+}
+
+func (u *userpassImpl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return u.client.doV1(ctx, method, path.Join("auth", u.MountPath, endpoint), params, body)
+}
+
+func (u *userpassImpl) WithMountPath(path string) Userpass {
+	uCopy := *u
+	uCopy.MountPath = path
+	u.client.Logger.Debug("using mount path: " + path)
+	return &uCopy
+}
+
+// Login exchanges a username and password for a token, which is also set as the client's token.
+// vault command: `vault login -method=userpass username={username}`
+func (u *userpassImpl) Login(username, password string, options *LoginOptions) (*AuthResult, error) {
+	return u.LoginCtx(context.Background(), username, password, options)
+}
+
+func (u *userpassImpl) LoginCtx(ctx context.Context, username, password string, options *LoginOptions) (*AuthResult, error) {
+	return passwordLogin(ctx, u.client, u.MountPath, username, password, options)
+}
+
+// vault command: `vault write auth/userpass/users/{username} password={password} token_policies={policies}`
+func (u *userpassImpl) CreateOrUpdateUser(username string, user *UserpassUser) error {
+	return u.CreateOrUpdateUserCtx(context.Background(), username, user)
+}
+
+func (u *userpassImpl) CreateOrUpdateUserCtx(ctx context.Context, username string, user *UserpassUser) error {
+	r, err := u.do(ctx, http.MethodPost, "users/"+username, nil, user)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	u.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault read auth/userpass/users/{username}`
+func (u *userpassImpl) ReadUser(username string) (*UserpassUser, error) {
+	return u.ReadUserCtx(context.Background(), username)
+}
+
+func (u *userpassImpl) ReadUserCtx(ctx context.Context, username string) (*UserpassUser, error) {
+	r, err := u.do(ctx, http.MethodGet, "users/"+username, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	u.client.Logger.Trace(r)
+	v := new(UserpassUser)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// vault command: `vault list auth/userpass/users`
+func (u *userpassImpl) ListUsers() ([]string, error) {
+	return u.ListUsersCtx(context.Background())
+}
+
+func (u *userpassImpl) ListUsersCtx(ctx context.Context) ([]string, error) {
+	r, err := u.do(ctx, http.MethodGet, "users", newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	u.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault delete auth/userpass/users/{username}`
+func (u *userpassImpl) DeleteUser(username string) error {
+	return u.DeleteUserCtx(context.Background(), username)
+}
+
+func (u *userpassImpl) DeleteUserCtx(ctx context.Context, username string) error {
+	r, err := u.do(ctx, http.MethodDelete, "users/"+username, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	u.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault write auth/userpass/users/{username}/password password={password}`
+func (u *userpassImpl) UpdatePassword(username, password string) error {
+	return u.UpdatePasswordCtx(context.Background(), username, password)
+}
+
+func (u *userpassImpl) UpdatePasswordCtx(ctx context.Context, username, password string) error {
+	body := map[string]interface{}{"password": password}
+	r, err := u.do(ctx, http.MethodPost, "users/"+username+"/password", nil, body)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	u.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault write auth/userpass/users/{username}/policies token_policies={policies}`
+func (u *userpassImpl) UpdatePolicies(username string, policies []string) error {
+	return u.UpdatePoliciesCtx(context.Background(), username, policies)
+}
+
+func (u *userpassImpl) UpdatePoliciesCtx(ctx context.Context, username string, policies []string) error {
+	body := map[string]interface{}{"token_policies": policies}
+	r, err := u.do(ctx, http.MethodPost, "users/"+username+"/policies", nil, body)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	u.client.Logger.Trace(r)
+	return nil
+}
+
+// passwordLogin logs in to a username and password auth method mounted at mountPath, ie userpass or LDAP, and sets
+// the resulting token on client.
+func passwordLogin(ctx context.Context, client *Client, mountPath, username, password string, options *LoginOptions) (*AuthResult, error) {
+	ctx = withLogin(ctx)
+	if options != nil && options.MFA != "" {
+		ctx = withHeader(ctx, "X-Vault-MFA", options.MFA)
+	}
+	body := map[string]interface{}{"password": password}
+	r, err := client.doV1(ctx, http.MethodPost, path.Join("auth", mountPath, "login", username), nil, body)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := parseAuth(r)
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(auth.ClientToken)
+	return auth, nil
+}
@@ -0,0 +1,71 @@
+package govault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func Test_passwordLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    func(c *Client) (*AuthResult, error)
+		wantPath string
+		wantMFA  string
+	}{
+		{
+			name: "Userpass",
+			login: func(c *Client) (*AuthResult, error) {
+				return c.Userpass().Login("alice", "hunter2", nil)
+			},
+			wantPath: "/v1/auth/userpass/login/alice",
+		},
+		{
+			name: "UserpassMFA",
+			login: func(c *Client) (*AuthResult, error) {
+				return c.Userpass().WithMountPath("local").Login("alice", "hunter2", &LoginOptions{MFA: "totp:123456"})
+			},
+			wantPath: "/v1/auth/local/login/alice",
+			wantMFA:  "totp:123456",
+		},
+		{
+			name: "LDAP",
+			login: func(c *Client) (*AuthResult, error) {
+				return c.LDAP().Login("alice", "hunter2", nil)
+			},
+			wantPath: "/v1/auth/ldap/login/alice",
+		},
+		{
+			name: "LDAPAuthMethod",
+			login: func(c *Client) (*AuthResult, error) {
+				method := &LDAPLogin{MountPath: "corp-ldap", Username: "alice", Password: "hunter2", Options: &LoginOptions{MFA: "totp:123456"}}
+				return method.Login(context.Background(), c)
+			},
+			wantPath: "/v1/auth/corp-ldap/login/alice",
+			wantMFA:  "totp:123456",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotMFA string
+			var gotBody map[string]string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotMFA = r.Header.Get("X-Vault-MFA")
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				w.Write([]byte(`{"auth":{"client_token":"s.user","policies":["dev"]}}`))
+			})
+			got, err := tt.login(c)
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			if gotPath != tt.wantPath || gotMFA != tt.wantMFA || gotBody["password"] != "hunter2" {
+				t.Errorf("Login() request = %s MFA %q body %v, want %s MFA %q", gotPath, gotMFA, gotBody, tt.wantPath, tt.wantMFA)
+			}
+			if got.ClientToken != "s.user" || c.Token != "s.user" {
+				t.Errorf("Login() token = %q, client token = %q, want %q", got.ClientToken, c.Token, "s.user")
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package govault
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvVaultAddress       = "VAULT_ADDR"
+	EnvVaultAgentAddress  = "VAULT_AGENT_ADDR"
+	EnvVaultToken         = "VAULT_TOKEN"
+	EnvVaultNamespace     = "VAULT_NAMESPACE"
+	EnvVaultCACert        = "VAULT_CACERT"
+	EnvVaultCAPath        = "VAULT_CAPATH"
+	EnvVaultClientCert    = "VAULT_CLIENT_CERT"
+	EnvVaultClientKey     = "VAULT_CLIENT_KEY"
+	EnvVaultSkipVerify    = "VAULT_SKIP_VERIFY"
+	EnvVaultTLSServerName = "VAULT_TLS_SERVER_NAME"
+	EnvVaultClientTimeout = "VAULT_CLIENT_TIMEOUT"
+	EnvVaultMaxRetries    = "VAULT_MAX_RETRIES"
+
+	DefaultAddress       = "http://127.0.0.1:8200"
+	DefaultClientTimeout = 60 * time.Second
+	DefaultMaxRetries    = DefaultRetryMaxAttempts - 1
+)
+
+// Config holds everything needed to construct a Client. Use DefaultConfig to get sensible defaults, ReadEnvironment
+// to apply the standard Vault environment variables on top of them, and NewClientFromConfig to build the Client.
+type Config struct {
+	// Address is the address of the Vault server. AgentAddress, if set, takes precedence so that requests are sent
+	// through a local Vault Agent instead.
+	Address      string
+	AgentAddress string
+
+	Token     string
+	Namespace string
+
+	// CACert is a PEM-encoded CA bundle file and CAPath a directory of PEM-encoded CA certificates used to verify
+	// the server. ClientCert and ClientKey are a PEM-encoded certificate and key presented to the server.
+	CACert        string
+	CAPath        string
+	ClientCert    string
+	ClientKey     string
+	TLSServerName string
+	Insecure      bool
+
+	// Timeout is the overall timeout of a single HTTP request. MaxRetries is the number of times a request failing
+	// with a transient error is retried; zero disables retries.
+	Timeout    time.Duration
+	MaxRetries int
+
+	Logger Logger
+}
+
+// DefaultConfig returns a Config pointing at a local Vault server with the default timeout and retry count.
+func DefaultConfig() *Config {
+	return &Config{
+		Address:    DefaultAddress,
+		Timeout:    DefaultClientTimeout,
+		MaxRetries: DefaultMaxRetries,
+		Logger:     NewStdLogger(),
+	}
+}
+
+// EnvironmentErrors lists the malformed environment variables found by Config.ReadEnvironment.
+type EnvironmentErrors []error
+
+func (e EnvironmentErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ReadEnvironment overrides fields of c with the standard Vault environment variables that are set. Malformed
+// values are skipped, leaving the corresponding fields of c unchanged, and returned together as EnvironmentErrors
+// once the remaining variables are read.
+func (c *Config) ReadEnvironment() error {
+	var errs EnvironmentErrors
+	if v := os.Getenv(EnvVaultAddress); v != "" {
+		if err := validateAddress(EnvVaultAddress, v); err != nil {
+			errs = append(errs, err)
+		} else {
+			c.Address = v
+		}
+	}
+	if v := os.Getenv(EnvVaultAgentAddress); v != "" {
+		if err := validateAddress(EnvVaultAgentAddress, v); err != nil {
+			errs = append(errs, err)
+		} else {
+			c.AgentAddress = v
+		}
+	}
+	if v := os.Getenv(EnvVaultToken); v != "" {
+		c.Token = v
+	}
+	if v := os.Getenv(EnvVaultNamespace); v != "" {
+		c.Namespace = v
+	}
+	if v := os.Getenv(EnvVaultCACert); v != "" {
+		c.CACert = v
+	}
+	if v := os.Getenv(EnvVaultCAPath); v != "" {
+		c.CAPath = v
+	}
+	clientCert, clientKey := os.Getenv(EnvVaultClientCert), os.Getenv(EnvVaultClientKey)
+	if (clientCert == "") != (clientKey == "") {
+		errs = append(errs, fmt.Errorf("%s and %s must be set together", EnvVaultClientCert, EnvVaultClientKey))
+	} else if clientCert != "" {
+		c.ClientCert, c.ClientKey = clientCert, clientKey
+	}
+	if v := os.Getenv(EnvVaultSkipVerify); v != "" {
+		if insecure, err := strconv.ParseBool(v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %q: %w", EnvVaultSkipVerify, v, err))
+		} else {
+			c.Insecure = insecure
+		}
+	}
+	if v := os.Getenv(EnvVaultTLSServerName); v != "" {
+		c.TLSServerName = v
+	}
+	if v := os.Getenv(EnvVaultClientTimeout); v != "" {
+		if timeout, err := parseDurationSeconds(v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %q: %w", EnvVaultClientTimeout, v, err))
+		} else {
+			c.Timeout = timeout
+		}
+	}
+	if v := os.Getenv(EnvVaultMaxRetries); v != "" {
+		if retries, err := strconv.Atoi(v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %q: %w", EnvVaultMaxRetries, v, err))
+		} else if retries < 0 {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %q: must not be negative", EnvVaultMaxRetries, v))
+		} else {
+			c.MaxRetries = retries
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// NewClientFromConfig constructs a Vault client from config.
+func NewClientFromConfig(config *Config) (*Client, error) {
+	transport, err := config.TLSConfig().Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	address := config.Address
+	if config.AgentAddress != "" {
+		address = config.AgentAddress
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = NewStdLogger()
+	}
+	var retryPolicy *RetryPolicy
+	if config.MaxRetries > 0 {
+		retryPolicy = NewDefaultRetryPolicy()
+		retryPolicy.MaxAttempts = config.MaxRetries + 1
+	}
+
+	return &Client{
+		httpClient:  &http.Client{Transport: transport, Timeout: config.Timeout},
+		Address:     address,
+		Token:       config.Token,
+		Namespace:   config.Namespace,
+		Logger:      logger,
+		RetryPolicy: retryPolicy,
+		auth:        &authState{},
+		mounts:      newMountCache(),
+	}, nil
+}
+
+// TLSConfig returns the TLS settings of c.
+func (c *Config) TLSConfig() *TLSConfig {
+	return &TLSConfig{
+		CACert:     c.CACert,
+		CAPath:     c.CAPath,
+		ClientCert: c.ClientCert,
+		ClientKey:  c.ClientKey,
+		ServerName: c.TLSServerName,
+		Insecure:   c.Insecure,
+	}
+}
+
+func validateAddress(env, address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %q: %w", env, address, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid value for %s: %q: must be an absolute URL such as %s", env, address, DefaultAddress)
+	}
+	return nil
+}
+
+// parseDurationSeconds parses a duration given either as a whole number of seconds or as a Go duration string.
+func parseDurationSeconds(value string) (time.Duration, error) {
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if d, err = time.ParseDuration(value); err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return d, nil
+}
@@ -0,0 +1,160 @@
+package govault
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setEnv sets the given environment variables for the duration of the test, restoring the previous values after.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for k, v := range env {
+		k := k
+		old, ok := os.LookupEnv(k)
+		os.Setenv(k, v)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestConfig_ReadEnvironment(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(c *Config) bool
+		wantErr bool
+	}{
+		{
+			name: "AllValid",
+			env: map[string]string{
+				EnvVaultAddress:       "https://vault.example.com:8200",
+				EnvVaultAgentAddress:  "http://127.0.0.1:8100",
+				EnvVaultNamespace:     "team-a",
+				EnvVaultSkipVerify:    "true",
+				EnvVaultTLSServerName: "vault.internal",
+				EnvVaultClientTimeout: "30",
+				EnvVaultMaxRetries:    "5",
+			},
+			check: func(c *Config) bool {
+				return c.Address == "https://vault.example.com:8200" &&
+					c.AgentAddress == "http://127.0.0.1:8100" &&
+					c.Namespace == "team-a" &&
+					c.Insecure &&
+					c.TLSServerName == "vault.internal" &&
+					c.Timeout == 30*time.Second &&
+					c.MaxRetries == 5
+			},
+		},
+		{
+			name:  "TimeoutDurationString",
+			env:   map[string]string{EnvVaultClientTimeout: "1m30s"},
+			check: func(c *Config) bool { return c.Timeout == 90*time.Second },
+		},
+		{
+			name:    "MalformedSkipVerify",
+			env:     map[string]string{EnvVaultSkipVerify: "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "MalformedTimeout",
+			env:     map[string]string{EnvVaultClientTimeout: "soon"},
+			wantErr: true,
+		},
+		{
+			name:    "NegativeMaxRetries",
+			env:     map[string]string{EnvVaultMaxRetries: "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "RelativeAddress",
+			env:     map[string]string{EnvVaultAddress: "vault:8200"},
+			wantErr: true,
+		},
+		{
+			name:    "ClientCertWithoutKey",
+			env:     map[string]string{EnvVaultClientCert: "/tmp/cert.pem"},
+			check:   func(c *Config) bool { return c.ClientCert == "" && c.ClientKey == "" },
+			wantErr: true,
+		},
+		{
+			name: "MalformedValuesSkipped",
+			env: map[string]string{
+				EnvVaultAddress:       "vault:8200",
+				EnvVaultToken:         "s.token",
+				EnvVaultNamespace:     "team-a",
+				EnvVaultCACert:        "/tmp/ca.pem",
+				EnvVaultSkipVerify:    "maybe",
+				EnvVaultClientTimeout: "45",
+			},
+			check: func(c *Config) bool {
+				return c.Address == DefaultAddress &&
+					c.Token == "s.token" &&
+					c.Namespace == "team-a" &&
+					c.CACert == "/tmp/ca.pem" &&
+					!c.Insecure &&
+					c.Timeout == 45*time.Second
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			c := DefaultConfig()
+			err := c.ReadEnvironment()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil && !tt.check(c) {
+				t.Errorf("ReadEnvironment() got = %+v", c)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.AgentAddress = "http://127.0.0.1:8100"
+	config.Namespace = "team-a"
+	config.MaxRetries = 0
+
+	c, err := NewClientFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() error = %v", err)
+	}
+	if c.Address != config.AgentAddress || c.Namespace != config.Namespace || c.RetryPolicy != nil {
+		t.Errorf("NewClientFromConfig() got = %+v", c)
+	}
+}
+
+func TestNewDefaultClient_InvalidClientCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caFile, _, _ := writeTestCert(t, dir, "vault-ca")
+	setEnv(t, map[string]string{
+		EnvVaultCACert:     caFile,
+		EnvVaultClientCert: filepath.Join(dir, "missing-cert.pem"),
+		EnvVaultClientKey:  filepath.Join(dir, "missing-key.pem"),
+	})
+
+	c := NewDefaultClient()
+	tlsConfig := c.httpClient.Transport.(*http.Transport).TLSClientConfig
+	if tlsConfig.RootCAs == nil {
+		t.Error("NewDefaultClient() dropped the CA certificate")
+	}
+	if tlsConfig.GetClientCertificate != nil {
+		t.Error("NewDefaultClient() kept the invalid client certificate")
+	}
+}
@@ -1,7 +1,14 @@
 // https://www.vaultproject.io/api#http-status-codes
 package govault
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
 
 type (
 	ErrSuccessNoData       struct{}
@@ -17,48 +24,171 @@ type (
 	ErrUnknownStatusCode struct {
 		StatusCode int
 	}
+
+	// ResponseError is returned for every non-200 response from Vault. It carries the request that failed and the
+	// details from Vault's JSON error body, and unwraps to the status-specific error (ErrForbidden, ErrInvalidPath,
+	// etc.), so errors.Is(err, &ErrForbidden{}) and errors.As(err, &forbidden) keep working.
+	ResponseError struct {
+		StatusCode int
+		Method     string
+		Path       string
+		Errors     []string
+		Warnings   []string
+		RequestID  string
+		Err        error
+	}
+
+	// ErrCanceled is returned when a request is abandoned because its context was canceled or its deadline was
+	// exceeded. Err is the context's error, so errors.Is(err, context.Canceled) and
+	// errors.Is(err, context.DeadlineExceeded) work as expected.
+	ErrCanceled struct {
+		Err error
+	}
 )
 
 func (e *ErrSuccessNoData) Error() string {
 	return "Success, no data returned."
 }
 
+func (e *ErrSuccessNoData) Is(target error) bool {
+	_, ok := target.(*ErrSuccessNoData)
+	return ok
+}
+
 func (e *ErrInvalidRequest) Error() string {
 	return "Invalid request, missing or invalid data."
 }
 
+func (e *ErrInvalidRequest) Is(target error) bool {
+	_, ok := target.(*ErrInvalidRequest)
+	return ok
+}
+
 func (e *ErrForbidden) Error() string {
 	return "Forbidden, your authentication details are either incorrect, you don't have access to this feature, or - if CORS is enabled - you made a cross-origin request from an origin that is not allowed to make such requests."
 }
 
+func (e *ErrForbidden) Is(target error) bool {
+	_, ok := target.(*ErrForbidden)
+	return ok
+}
+
 func (e *ErrInvalidPath) Error() string {
 	return "Invalid path. This can both mean that the path truly doesn't exist or that you don't have permission to view a specific path. We use 404 in some cases to avoid state leakage."
 }
 
+func (e *ErrInvalidPath) Is(target error) bool {
+	_, ok := target.(*ErrInvalidPath)
+	return ok
+}
+
 func (e *ErrStandby) Error() string {
 	return "Default return code for health status of standby nodes. This will likely change in the future."
 }
 
+func (e *ErrStandby) Is(target error) bool {
+	_, ok := target.(*ErrStandby)
+	return ok
+}
+
 func (e *ErrPerformanceStandby) Error() string {
 	return "Default return code for health status of performance standby nodes."
 }
 
+func (e *ErrPerformanceStandby) Is(target error) bool {
+	_, ok := target.(*ErrPerformanceStandby)
+	return ok
+}
+
 func (e *ErrInternalServerError) Error() string {
 	return "Internal server error. An internal error has occurred, try again later. If the error persists, report a bug."
 }
 
+func (e *ErrInternalServerError) Is(target error) bool {
+	_, ok := target.(*ErrInternalServerError)
+	return ok
+}
+
 func (e *ErrThirdPartyError) Error() string {
 	return "A request to Vault required Vault making a request to a third party; the third party responded with an error of some kind."
 }
 
+func (e *ErrThirdPartyError) Is(target error) bool {
+	_, ok := target.(*ErrThirdPartyError)
+	return ok
+}
+
 func (e *ErrSealed) Error() string {
 	return "Vault is down for maintenance or is currently sealed. Try again later."
 }
 
+func (e *ErrSealed) Is(target error) bool {
+	_, ok := target.(*ErrSealed)
+	return ok
+}
+
 func (e *ErrUnknownStatusCode) Error() string {
 	return fmt.Sprintf("Unknown status code: %d", e.StatusCode)
 }
 
+// Is matches any *ErrUnknownStatusCode target whose StatusCode is zero or equal to e.StatusCode.
+func (e *ErrUnknownStatusCode) Is(target error) bool {
+	t, ok := target.(*ErrUnknownStatusCode)
+	return ok && (t.StatusCode == 0 || t.StatusCode == e.StatusCode)
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("%s %s: %v", e.Method, e.Path, e.Err)
+	if len(e.Errors) > 0 {
+		msg += " Errors: " + strings.Join(e.Errors, "; ")
+	}
+	return msg
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// newResponseError builds a *ResponseError from a non-200 response, reading and closing its body.
+func newResponseError(resp *http.Response) *ResponseError {
+	defer resp.Body.Close()
+	e := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Method:     resp.Request.Method,
+		Path:       resp.Request.URL.Path,
+		Err:        checkStatus(resp.StatusCode),
+	}
+	body := new(struct {
+		Errors    []string `json:"errors"`
+		Warnings  []string `json:"warnings"`
+		RequestID string   `json:"request_id"`
+	})
+	if b, err := ioutil.ReadAll(resp.Body); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, body); err == nil {
+			e.Errors = body.Errors
+			e.Warnings = body.Warnings
+			e.RequestID = body.RequestID
+		}
+	}
+	return e
+}
+
+func (e *ErrCanceled) Error() string {
+	return fmt.Sprintf("Request canceled: %v", e.Err)
+}
+
+func (e *ErrCanceled) Unwrap() error {
+	return e.Err
+}
+
+// contextError returns an *ErrCanceled if ctx is done, otherwise err unchanged.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &ErrCanceled{Err: ctxErr}
+	}
+	return err
+}
+
 func checkStatus(code int) error {
 	switch code {
 	case 200:
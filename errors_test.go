@@ -0,0 +1,67 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestResponseError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantIs     error
+		wantErrors []string
+		wantReqID  string
+	}{
+		{
+			name:       "Forbidden",
+			status:     http.StatusForbidden,
+			body:       `{"errors":["permission denied"],"request_id":"abc"}`,
+			wantIs:     &ErrForbidden{},
+			wantErrors: []string{"permission denied"},
+			wantReqID:  "abc",
+		},
+		{
+			name:       "InvalidRequestMultipleErrors",
+			status:     http.StatusBadRequest,
+			body:       `{"errors":["check-and-set parameter did not match the current version","second"]}`,
+			wantIs:     &ErrInvalidRequest{},
+			wantErrors: []string{"check-and-set parameter did not match the current version", "second"},
+		},
+		{
+			name:   "UnknownStatusCodeEmptyBody",
+			status: http.StatusMethodNotAllowed,
+			wantIs: &ErrUnknownStatusCode{StatusCode: http.StatusMethodNotAllowed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+			_, err := c.doV1(context.Background(), http.MethodGet, "secret/data/foo", nil, nil)
+			if !errors.Is(err, tt.wantIs) {
+				t.Fatalf("doV1() error = %v, want errors.Is %T", err, tt.wantIs)
+			}
+			var respErr *ResponseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("doV1() error = %T, want *ResponseError", err)
+			}
+			if respErr.StatusCode != tt.status || respErr.Method != http.MethodGet || respErr.Path != "/v1/secret/data/foo" {
+				t.Errorf("doV1() got = %+v", respErr)
+			}
+			if !reflect.DeepEqual(respErr.Errors, tt.wantErrors) || respErr.RequestID != tt.wantReqID {
+				t.Errorf("doV1() errors = %v, request ID = %q, want %v, %q", respErr.Errors, respErr.RequestID, tt.wantErrors, tt.wantReqID)
+			}
+		})
+	}
+}
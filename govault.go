@@ -2,22 +2,32 @@ package govault
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
+	"path"
+	"strings"
 )
 
 type (
 	Client struct {
-		httpClient *http.Client
-		Address    string
-		Token      string
-		Logger     Logger
+		httpClient  *http.Client
+		Address     string
+		Token       string
+		Namespace   string
+		Logger      Logger
+		RetryPolicy *RetryPolicy
+		Auth        AuthMethod
+
+		auth   *authState
+		mounts *mountCache
 	}
 
+	headerContextKey struct{}
+
 	vaultResponse struct {
 		RequestID     string      `json:"request_id"`
 		LeaseID       string      `json:"lease_id"`
@@ -30,68 +40,176 @@ type (
 	}
 )
 
-// NewClient constructs a Vault client.
+// NewClient constructs a Vault client. Requests are not retried unless RetryPolicy is set on the returned client.
 func NewClient(httpClient *http.Client, address, token string, logger Logger) *Client {
-	return &Client{httpClient, address, token, logger}
+	return &Client{
+		httpClient: httpClient,
+		Address:    address,
+		Token:      token,
+		Logger:     logger,
+		auth:       &authState{},
+		mounts:     newMountCache(),
+	}
 }
 
-// NewDefaultClient constructs a Vault client using environment variables VAULT_ADDR
-// and VAULT_TOKEN for address and token respectively.
+// NewDefaultClient constructs a Vault client from DefaultConfig and the standard Vault environment variables (see
+// Config.ReadEnvironment). Malformed environment variables are logged and skipped, as are CA or client certificates
+// that fail to load, which fall back to the system roots and no client certificate respectively; use
+// Config.ReadEnvironment and NewClientFromConfig to handle them as errors.
 func NewDefaultClient() *Client {
-	address := os.Getenv("VAULT_ADDR")
-	if address == "" {
-		address = "http://127.0.0.1:8200"
+	config := DefaultConfig()
+	if err := config.ReadEnvironment(); err != nil {
+		config.Logger.Error(err)
 	}
-	return &Client{&http.Client{}, address, os.Getenv("VAULT_TOKEN"), NewStdLogger()}
+	client, err := NewClientFromConfig(config)
+	var certErr *ErrCertificate
+	for errors.As(err, &certErr) {
+		config.Logger.Error(err)
+		if certErr.Client {
+			config.ClientCert, config.ClientKey = "", ""
+		} else {
+			config.CACert, config.CAPath = "", ""
+		}
+		client, err = NewClientFromConfig(config)
+	}
+	return client
 }
 
-func (c *Client) doV1(method, endpoint string, params map[string]interface{}, body interface{}) (*vaultResponse, error) {
-	// serialize request body
-	var reqBody io.Reader
+// WithNamespace returns a copy of the client whose requests target the Vault Enterprise namespace ns, sent as the
+// X-Vault-Namespace header. Namespaces nest: ns is relative to the client's current namespace, so
+// c.WithNamespace("a").WithNamespace("b") targets "a/b". A leading "/" makes ns relative to the root namespace
+// instead, and "/" alone targets the root namespace. The copy starts with the client's current token but logs in
+// again on its own, so that automatic re-authentication happens in the namespace it targets.
+func (c *Client) WithNamespace(ns string) *Client {
+	var cCopy Client
+	if c.auth == nil {
+		cCopy = *c
+	} else {
+		// the token may be replaced concurrently, so copy it under the lock
+		c.auth.mu.RLock()
+		cCopy = *c
+		c.auth.mu.RUnlock()
+		cCopy.auth = &authState{}
+	}
+	if strings.HasPrefix(ns, "/") {
+		cCopy.Namespace = strings.Trim(ns, "/")
+	} else {
+		cCopy.Namespace = strings.Trim(path.Join(c.Namespace, ns), "/")
+	}
+	c.Logger.Debug("using namespace: " + cCopy.Namespace)
+	return &cCopy
+}
+
+// doV1 executes a request against the Vault v1 HTTP API, retrying transient failures according to c.RetryPolicy.
+// If the request is forbidden and c.Auth is set, the client logs in again once and replays the request.
+// If ctx is canceled or its deadline is exceeded before the response has been read, the returned error is an
+// *ErrCanceled wrapping ctx.Err().
+func (c *Client) doV1(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	// serialize request body once so that it can be replayed on retries
+	var reqBody []byte
 	if body != nil {
 		b, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(b)
+		reqBody = b
 	}
 
-	// build request, add query parameters and headers
-	reqURL := c.Address + "/v1/" + endpoint
-	req, err := http.NewRequest(method, reqURL, reqBody)
-	if err != nil {
+	token := c.token()
+	r, err := c.doV1Retry(ctx, method, endpoint, params, reqBody, token)
+	if err == nil || c.Auth == nil || !errors.Is(err, &ErrForbidden{}) || isLogin(ctx) {
+		return r, err
+	}
+	c.Logger.Debug("re-authenticating after error:", err)
+	if err := c.reauthenticate(ctx, token); err != nil {
 		return nil, err
 	}
-	if params != nil {
-		for p, q := range params {
-			req.URL.Query().Set(p, fmt.Sprint(q))
+	return c.doV1Retry(ctx, method, endpoint, params, reqBody, c.token())
+}
+
+// doV1Retry executes a request, retrying transient failures according to c.RetryPolicy.
+func (c *Client) doV1Retry(ctx context.Context, method, endpoint string, params query, body []byte, token string) (*vaultResponse, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, endpoint, params, body, token)
+		if err != nil {
+			if ctx.Err() != nil || !c.RetryPolicy.shouldRetry(method, attempt, 0) {
+				return nil, contextError(ctx, err)
+			}
+			c.Logger.Debug("retrying request after error:", err)
+			if err := sleepCtx(ctx, c.RetryPolicy.backoff(attempt, nil)); err != nil {
+				return nil, err
+			}
+			continue
 		}
+
+		// check known status codes, retrying transient ones
+		if resp.StatusCode != http.StatusOK {
+			err := newResponseError(resp)
+			if !c.RetryPolicy.shouldRetry(method, attempt, resp.StatusCode) {
+				return nil, err
+			}
+			c.Logger.Debug("retrying request after error:", err)
+			if err := sleepCtx(ctx, c.RetryPolicy.backoff(attempt, resp)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return parseResponse(ctx, resp)
 	}
-	req.Header.Add("X-Vault-Token", c.Token)
-	req.Header.Add("X-Vault-Request", "true")
+}
 
-	// execute request
-	resp, err := c.httpClient.Do(req)
+// send builds and executes a single HTTP request against the Vault v1 HTTP API.
+func (c *Client) send(ctx context.Context, method, endpoint string, params query, body []byte, token string) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	// build request, add query parameters and headers
+	reqURL := c.Address + "/v1/" + endpoint
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	if len(params) > 0 {
+		req.URL.RawQuery = params.Encode()
+	}
+	req.Header.Add("X-Vault-Token", token)
+	req.Header.Add("X-Vault-Request", "true")
+	if c.Namespace != "" {
+		req.Header.Add("X-Vault-Namespace", c.Namespace)
+	}
+	if h, ok := ctx.Value(headerContextKey{}).(http.Header); ok {
+		for k, v := range h {
+			req.Header[k] = v
+		}
+	}
 
-	// check known status codes
-	if err := checkStatus(resp.StatusCode); err != nil {
-		return nil, err
+	return c.httpClient.Do(req)
+}
+
+// withHeader returns a copy of ctx that adds the header key: value to requests made with it.
+func withHeader(ctx context.Context, key, value string) context.Context {
+	h := http.Header{}
+	if parent, ok := ctx.Value(headerContextKey{}).(http.Header); ok {
+		h = parent.Clone()
 	}
+	h.Set(key, value)
+	return context.WithValue(ctx, headerContextKey{}, h)
+}
 
-	// parse response
+// parseResponse reads and decodes a successful response, closing its body.
+func parseResponse(ctx context.Context, resp *http.Response) (*vaultResponse, error) {
+	defer resp.Body.Close()
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, contextError(ctx, err)
 	}
 	v := new(vaultResponse)
 	if err := json.Unmarshal(respBody, v); err != nil {
 		return nil, err
 	}
-
 	return v, nil
 }
 
@@ -107,3 +225,14 @@ func typeConvert(from, toPtr interface{}) error {
 	}
 	return nil
 }
+
+// parseKeys decodes the keys returned by a LIST request.
+func parseKeys(r *vaultResponse) ([]string, error) {
+	data := new(struct {
+		Keys []string `json:"keys"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return nil, err
+	}
+	return data.Keys, nil
+}
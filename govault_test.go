@@ -0,0 +1,107 @@
+package govault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newTestClient starts an httptest server running handler and returns a client pointed at it. The server is closed
+// when the test finishes.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+}
+
+// requestTest is a client call that makes a single request, checked by runRequestTests. response is the body Vault
+// replies with, or a 204 if empty, and want the expected result if not nil.
+type requestTest struct {
+	name      string
+	call      func(c *Client) (interface{}, error)
+	response  string
+	wantReq   string
+	wantQuery string
+	wantBody  map[string]interface{}
+	want      interface{}
+}
+
+// runRequestTests runs each call against a test server, checking the method, path, query and JSON body of the
+// request it makes and the result it decodes.
+func runRequestTests(t *testing.T, tests []requestTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq, gotQuery string
+			var gotBody map[string]interface{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				gotReq = r.Method + " " + r.URL.Path
+				gotQuery = r.URL.RawQuery
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				if tt.response == "" {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				w.Write([]byte(tt.response))
+			})
+			got, err := tt.call(c)
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if gotReq != tt.wantReq || gotQuery != tt.wantQuery || !reflect.DeepEqual(gotBody, tt.wantBody) {
+				t.Errorf("%s() request = %s?%s %v, want %s?%s %v", tt.name, gotReq, gotQuery, gotBody, tt.wantReq, tt.wantQuery, tt.wantBody)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() got = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_WithNamespace(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		want       string
+	}{
+		{"None", nil, ""},
+		{"Single", []string{"team-a"}, "team-a"},
+		{"Nested", []string{"team-a", "project-1"}, "team-a/project-1"},
+		{"NestedPath", []string{"team-a/", "project-1/env"}, "team-a/project-1/env"},
+		{"AbsoluteResets", []string{"team-a", "/team-b"}, "team-b"},
+		{"Root", []string{"team-a", "/"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHeader string
+			var present bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, present = r.Header["X-Vault-Namespace"]
+				gotHeader = r.Header.Get("X-Vault-Namespace")
+				w.Write([]byte(`{"data":{"data":{}}}`))
+			}))
+			defer srv.Close()
+
+			base := NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+			c := base
+			for _, ns := range tt.namespaces {
+				c = c.WithNamespace(ns)
+			}
+			if c.Namespace != tt.want {
+				t.Errorf("WithNamespace() namespace = %q, want %q", c.Namespace, tt.want)
+			}
+			if base.Namespace != "" {
+				t.Errorf("WithNamespace() modified the original client")
+			}
+			if _, err := c.KVv2().ReadSecretVersion("foo", 0); err != nil {
+				t.Fatalf("ReadSecretVersion() error = %v", err)
+			}
+			if gotHeader != tt.want || present != (tt.want != "") {
+				t.Errorf("X-Vault-Namespace = %q (present %v), want %q", gotHeader, present, tt.want)
+			}
+		})
+	}
+}
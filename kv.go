@@ -0,0 +1,163 @@
+package govault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type (
+	// KV is the subset of operations common to KV v1 and v2 mounts, see Client.KV. On a KV v2 mount, Get reads and
+	// Delete soft-deletes the latest version.
+	KV interface {
+		Version() int
+		Get(path string) (map[string]interface{}, error)
+		GetCtx(ctx context.Context, path string) (map[string]interface{}, error)
+		Put(path string, data map[string]interface{}) error
+		PutCtx(ctx context.Context, path string, data map[string]interface{}) error
+		Delete(path string) error
+		DeleteCtx(ctx context.Context, path string) error
+		List(path string) ([]string, error)
+		ListCtx(ctx context.Context, path string) ([]string, error)
+	}
+
+	kvv1Adapter struct {
+		KVv1
+	}
+
+	kvv2Adapter struct {
+		kvv2 KVv2
+	}
+
+	// mountCache remembers the detected KV version of each mount. It is shared by copies of a Client.
+	mountCache struct {
+		mu       sync.Mutex
+		versions map[string]int
+	}
+)
+
+// KV returns a client for the KV mount at mountPath, detecting whether it is a version 1 or 2 mount. Detection
+// results are cached per namespace and mount path for the lifetime of the client.
+func (c *Client) KV(mountPath string) (KV, error) {
+	return c.KVCtx(context.Background(), mountPath)
+}
+
+func (c *Client) KVCtx(ctx context.Context, mountPath string) (KV, error) {
+	mountPath = strings.Trim(mountPath, "/")
+	version, err := c.kvVersion(ctx, mountPath)
+	if err != nil {
+		return nil, err
+	}
+	if version == 2 {
+		return &kvv2Adapter{kvv2: c.KVv2().WithMountPath(mountPath)}, nil
+	}
+	return &kvv1Adapter{KVv1: c.KVv1().WithMountPath(mountPath)}, nil
+}
+
+// kvVersion returns the version of the KV mount at mountPath, looking it up in sys/internal/ui/mounts if it is not
+// cached yet.
+func (c *Client) kvVersion(ctx context.Context, mountPath string) (int, error) {
+	key := c.Namespace + "|" + mountPath
+	if c.mounts != nil {
+		c.mounts.mu.Lock()
+		version, ok := c.mounts.versions[key]
+		c.mounts.mu.Unlock()
+		if ok {
+			return version, nil
+		}
+	}
+
+	r, err := c.doV1(ctx, http.MethodGet, "sys/internal/ui/mounts/"+mountPath, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.Logger.Trace(r)
+	data := new(struct {
+		Type    string            `json:"type"`
+		Options map[string]string `json:"options"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return 0, err
+	}
+	if data.Type != "kv" && data.Type != "generic" {
+		return 0, fmt.Errorf("mount %q is a %q mount, not a KV mount", mountPath, data.Type)
+	}
+	version := 1
+	if data.Options["version"] == "2" {
+		version = 2
+	}
+
+	if c.mounts != nil {
+		c.mounts.mu.Lock()
+		c.mounts.versions[key] = version
+		c.mounts.mu.Unlock()
+	}
+	return version, nil
+}
+
+func newMountCache() *mountCache {
+	return &mountCache{versions: map[string]int{}}
+}
+
+func (k *kvv1Adapter) Version() int {
+	return 1
+}
+
+func (k *kvv1Adapter) Get(path string) (map[string]interface{}, error) {
+	return k.ReadCtx(context.Background(), path)
+}
+
+func (k *kvv1Adapter) GetCtx(ctx context.Context, path string) (map[string]interface{}, error) {
+	return k.ReadCtx(ctx, path)
+}
+
+func (k *kvv1Adapter) Put(path string, data map[string]interface{}) error {
+	return k.WriteCtx(context.Background(), path, data)
+}
+
+func (k *kvv1Adapter) PutCtx(ctx context.Context, path string, data map[string]interface{}) error {
+	return k.WriteCtx(ctx, path, data)
+}
+
+func (k *kvv2Adapter) Version() int {
+	return 2
+}
+
+func (k *kvv2Adapter) Get(path string) (map[string]interface{}, error) {
+	return k.GetCtx(context.Background(), path)
+}
+
+func (k *kvv2Adapter) GetCtx(ctx context.Context, path string) (map[string]interface{}, error) {
+	secret, err := k.kvv2.ReadSecretVersionCtx(ctx, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+func (k *kvv2Adapter) Put(path string, data map[string]interface{}) error {
+	return k.PutCtx(context.Background(), path, data)
+}
+
+func (k *kvv2Adapter) PutCtx(ctx context.Context, path string, data map[string]interface{}) error {
+	_, err := k.kvv2.CreateOrUpdateSecretCtx(ctx, path, data, nil)
+	return err
+}
+
+func (k *kvv2Adapter) Delete(path string) error {
+	return k.kvv2.DeleteLatestSecretVersionCtx(context.Background(), path)
+}
+
+func (k *kvv2Adapter) DeleteCtx(ctx context.Context, path string) error {
+	return k.kvv2.DeleteLatestSecretVersionCtx(ctx, path)
+}
+
+func (k *kvv2Adapter) List(path string) ([]string, error) {
+	return k.kvv2.ListSecretsCtx(context.Background(), path)
+}
+
+func (k *kvv2Adapter) ListCtx(ctx context.Context, path string) ([]string, error) {
+	return k.kvv2.ListSecretsCtx(ctx, path)
+}
@@ -0,0 +1,82 @@
+package govault
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_KV(t *testing.T) {
+	var lookups int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mount := strings.TrimPrefix(r.URL.Path, "/v1/sys/internal/ui/mounts/")
+		atomic.AddInt32(&lookups, 1)
+		data := map[string]interface{}{}
+		switch mount {
+		case "kv":
+			data = map[string]interface{}{"type": "kv", "options": nil}
+		case "secret":
+			data = map[string]interface{}{"type": "kv", "options": map[string]string{"version": "2"}}
+		case "transit":
+			data = map[string]interface{}{"type": "transit"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	})
+
+	tests := []struct {
+		name    string
+		mount   string
+		want    int
+		wantErr bool
+	}{
+		{"V1", "kv", 1, false},
+		{"V2", "secret/", 2, false},
+		{"NotKV", "transit", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kv, err := c.KV(tt.mount)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && kv.Version() != tt.want {
+				t.Errorf("KV().Version() = %d, want %d", kv.Version(), tt.want)
+			}
+		})
+	}
+
+	before := atomic.LoadInt32(&lookups)
+	if _, err := c.KV("secret"); err != nil {
+		t.Fatalf("KV() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&lookups); got != before {
+		t.Errorf("KV() looked up a cached mount, lookups = %d, want %d", got, before)
+	}
+	if _, err := c.WithNamespace("team-a").KV("secret"); err != nil {
+		t.Fatalf("KV() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&lookups); got != before+1 {
+		t.Errorf("KV() reused the cache across namespaces, lookups = %d, want %d", got, before+1)
+	}
+}
+
+func TestClient_KV_v1(t *testing.T) {
+	c := kvv1TestServer(t)
+	c.mounts.versions["|kv"] = 1
+	kv, err := c.KV("kv")
+	if err != nil {
+		t.Fatalf("KV() error = %v", err)
+	}
+	if err := kv.Put("db", map[string]interface{}{"port": float64(5432)}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := kv.Get("db")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got["port"] != float64(5432) {
+		t.Errorf("Get() = %v", got)
+	}
+}
@@ -0,0 +1,108 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+)
+
+const DefaultKVv1MountPath = "kv"
+
+type (
+	KVv1 interface {
+		WithMountPath(path string) KVv1
+		Read(path string) (map[string]interface{}, error)
+		ReadCtx(ctx context.Context, path string) (map[string]interface{}, error)
+		Write(path string, data map[string]interface{}) error
+		WriteCtx(ctx context.Context, path string, data map[string]interface{}) error
+		Delete(path string) error
+		DeleteCtx(ctx context.Context, path string) error
+		List(path string) ([]string, error)
+		ListCtx(ctx context.Context, path string) ([]string, error)
+	}
+
+	kvv1Impl struct {
+		client    *Client
+		MountPath string
+	}
+)
+
+func (c *Client) KVv1() KVv1 {
+	return &kvv1Impl{
+		client:    c,
+		MountPath: DefaultKVv1MountPath,
+	}
+}
+
+func (k *kvv1Impl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return k.client.doV1(ctx, method, path.Join(k.MountPath, endpoint), params, body)
+}
+
+func (k *kvv1Impl) WithMountPath(path string) KVv1 {
+	kCopy := *k
+	kCopy.MountPath = path
+	k.client.Logger.Debug("using mount path: " + path)
+	return &kCopy
+}
+
+// vault command: `vault kv get kv/{path}`
+func (k *kvv1Impl) Read(path string) (map[string]interface{}, error) {
+	return k.ReadCtx(context.Background(), path)
+}
+
+func (k *kvv1Impl) ReadCtx(ctx context.Context, path string) (map[string]interface{}, error) {
+	r, err := k.do(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	k.client.Logger.Trace(r)
+	var v map[string]interface{}
+	if err := typeConvert(r.Data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Write replaces the secret at path with data. Values may be any JSON-encodable type.
+// vault command: `vault kv put kv/{path} mykey=myval`
+func (k *kvv1Impl) Write(path string, data map[string]interface{}) error {
+	return k.WriteCtx(context.Background(), path, data)
+}
+
+func (k *kvv1Impl) WriteCtx(ctx context.Context, path string, data map[string]interface{}) error {
+	r, err := k.do(ctx, http.MethodPut, path, nil, data)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	k.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault kv delete kv/{path}`
+func (k *kvv1Impl) Delete(path string) error {
+	return k.DeleteCtx(context.Background(), path)
+}
+
+func (k *kvv1Impl) DeleteCtx(ctx context.Context, path string) error {
+	r, err := k.do(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	k.client.Logger.Trace(r)
+	return nil
+}
+
+// vault command: `vault kv list kv/{path}`
+func (k *kvv1Impl) List(path string) ([]string, error) {
+	return k.ListCtx(context.Background(), path)
+}
+
+func (k *kvv1Impl) ListCtx(ctx context.Context, path string) ([]string, error) {
+	r, err := k.do(ctx, http.MethodGet, path, newQuery().List(), nil)
+	if err != nil {
+		return nil, err
+	}
+	k.client.Logger.Trace(r)
+	return parseKeys(r)
+}
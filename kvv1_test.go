@@ -0,0 +1,76 @@
+package govault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// kvv1TestServer is an in-memory KV v1 mount at "kv".
+func kvv1TestServer(t *testing.T) *Client {
+	secrets := map[string]map[string]interface{}{}
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/v1/kv"), "/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("list") == "true":
+			keys := []string{}
+			for k := range secrets {
+				keys = append(keys, k)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+		case r.Method == http.MethodGet:
+			data, ok := secrets[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		case r.Method == http.MethodPut:
+			b, _ := ioutil.ReadAll(r.Body)
+			var data map[string]interface{}
+			json.Unmarshal(b, &data)
+			secrets[key] = data
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			delete(secrets, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func Test_kvv1Impl(t *testing.T) {
+	k := kvv1TestServer(t).KVv1()
+	data := map[string]interface{}{
+		"user":    "admin",
+		"port":    float64(5432),
+		"enabled": true,
+		"nested":  map[string]interface{}{"a": "b"},
+	}
+
+	if err := k.Write("db", data); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, err := k.Read("db")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, data) {
+		t.Errorf("Read() got = %v, want %v", got, data)
+	}
+	keys, err := k.List("")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"db"}) {
+		t.Errorf("List() got = %v, want %v", keys, []string{"db"})
+	}
+	if err := k.Delete("db"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := k.Read("db"); err == nil {
+		t.Errorf("Read() after Delete() error = nil, want %v", &ErrInvalidPath{})
+	}
+}
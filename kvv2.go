@@ -1,71 +1,142 @@
 package govault
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"strings"
 	"time"
 )
 
-const DefaultKVv2MountPath = "secret"
+const (
+	DefaultKVv2MountPath = "secret"
+
+	// DefaultKVv2CASRetries is how many times UpdateSecret retries after a check-and-set conflict.
+	DefaultKVv2CASRetries = 5
+)
 
 type (
 	KVv2 interface {
 		WithMountPath(path string) KVv2
 		Configure(options *KVv2Config) error
+		ConfigureCtx(ctx context.Context, options *KVv2Config) error
 		ReadConfig() (*KVv2Config, error)
+		ReadConfigCtx(ctx context.Context) (*KVv2Config, error)
 		ReadSecretVersion(path string, version int) (*KVv2Secret, error)
-		CreateOrUpdateSecret(path string, data map[string]string, options *KVv2CreateOrUpdateSecretOptions) error
+		ReadSecretVersionCtx(ctx context.Context, path string, version int) (*KVv2Secret, error)
+		CreateOrUpdateSecret(path string, data map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
+		CreateOrUpdateSecretCtx(ctx context.Context, path string, data map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
+		PatchSecret(path string, patch map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
+		PatchSecretCtx(ctx context.Context, path string, patch map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
+		UpdateSecret(path string, update KVv2UpdateFunc) (int, error)
+		UpdateSecretCtx(ctx context.Context, path string, update KVv2UpdateFunc) (int, error)
+		ReadSecretInto(path string, v interface{}) error
+		ReadSecretIntoCtx(ctx context.Context, path string, v interface{}) error
+		WriteSecretFrom(path string, v interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
+		WriteSecretFromCtx(ctx context.Context, path string, v interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error)
 		DeleteLatestSecretVersion(path string) error
+		DeleteLatestSecretVersionCtx(ctx context.Context, path string) error
 		DeleteSecretVersions(path string, versions []int) error
+		DeleteSecretVersionsCtx(ctx context.Context, path string, versions []int) error
 		UndeleteSecretVersions(path string, versions []int) error
+		UndeleteSecretVersionsCtx(ctx context.Context, path string, versions []int) error
 		DestroySecretVersions(path string, versions []int) error
+		DestroySecretVersionsCtx(ctx context.Context, path string, versions []int) error
 		ListSecrets(path string) ([]string, error)
-		ReadSecretMetadata(path string) error
-		UpdateMetadata(path string, maxVersions int, casRequired bool, deleteVersionAfter time.Duration) error
+		ListSecretsCtx(ctx context.Context, path string) ([]string, error)
+		ListSecretsRecursive(root string, options *KVv2WalkOptions) ([]string, error)
+		ListSecretsRecursiveCtx(ctx context.Context, root string, options *KVv2WalkOptions) ([]string, error)
+		WalkSecrets(root string, fn KVv2WalkFunc, options *KVv2WalkOptions) error
+		WalkSecretsCtx(ctx context.Context, root string, fn KVv2WalkFunc, options *KVv2WalkOptions) error
+		ReadSecretMetadata(path string) (*KVv2Metadata, error)
+		ReadSecretMetadataCtx(ctx context.Context, path string) (*KVv2Metadata, error)
+		UpdateMetadata(path string, options *KVv2UpdateMetadataOptions) error
+		UpdateMetadataCtx(ctx context.Context, path string, options *KVv2UpdateMetadataOptions) error
 		DeleteMetadataAndAllVersions(path string) error
+		DeleteMetadataAndAllVersionsCtx(ctx context.Context, path string) error
+		ExportMount(w io.Writer, root string, options *KVv2ExportOptions) (int, error)
+		ExportMountCtx(ctx context.Context, w io.Writer, root string, options *KVv2ExportOptions) (int, error)
+		ImportMount(r io.Reader, root string, options *KVv2ImportOptions) (*KVv2ImportResult, error)
+		ImportMountCtx(ctx context.Context, r io.Reader, root string, options *KVv2ImportOptions) (*KVv2ImportResult, error)
+		WithCASRetries(retries int) KVv2
 	}
 
 	kvv2Impl struct {
-		client    *Client
-		MountPath string
+		client     *Client
+		MountPath  string
+		CASRetries int
 	}
 
+	// KVv2UpdateFunc computes the new data of a secret from its current data, which is nil if the secret does not
+	// exist or its latest version is deleted. The current map may be modified and returned.
+	KVv2UpdateFunc func(current map[string]interface{}) (map[string]interface{}, error)
+
 	KVv2Config struct {
 		MaxVersions        int    `json:"max_versions"`
 		CASRequired        bool   `json:"cas_required"`
 		DeleteVersionAfter string `json:"delete_version_after,omitempty"`
 	}
 
+	// KVv2Secret is a version of a secret. Data holds arbitrary JSON values, so numbers decode as float64 and
+	// objects as map[string]interface{}; use Decode to convert it into a struct.
 	KVv2Secret struct {
-		Data     map[string]string `json:"data"`
-		Metadata struct {
-			CreatedTime  string `json:"created_time"`
-			DeletionTime string `json:"deletion_time"`
-			Destroyed    bool   `json:"destroyed"`
-			Version      int    `json:"version"`
-		} `json:"metadata"`
+		Data     map[string]interface{}    `json:"data"`
+		Metadata KVv2SecretVersionMetadata `json:"metadata"`
 	}
 
 	kvv2CreateOrUpdateSecretRequest struct {
-		Data    map[string]string               `json:"data"`
+		Data    map[string]interface{}          `json:"data"`
 		Options KVv2CreateOrUpdateSecretOptions `json:"options"`
 	}
 
 	KVv2CreateOrUpdateSecretOptions struct {
 		CAS int `json:"cas,omitempty"`
 	}
+
+	// KVv2Metadata is the metadata of a secret and all of its versions. Versions is keyed by version number.
+	KVv2Metadata struct {
+		CASRequired        bool                              `json:"cas_required"`
+		CreatedTime        string                            `json:"created_time"`
+		CurrentVersion     int                               `json:"current_version"`
+		CustomMetadata     map[string]string                 `json:"custom_metadata"`
+		DeleteVersionAfter string                            `json:"delete_version_after"`
+		MaxVersions        int                               `json:"max_versions"`
+		OldestVersion      int                               `json:"oldest_version"`
+		UpdatedTime        string                            `json:"updated_time"`
+		Versions           map[int]KVv2SecretVersionMetadata `json:"versions"`
+	}
+
+	// KVv2SecretVersionMetadata is the state of a single secret version, as returned when reading or writing it.
+	// DeletionTime is empty unless the version is deleted or scheduled for deletion.
+	KVv2SecretVersionMetadata struct {
+		CreatedTime  string `json:"created_time"`
+		DeletionTime string `json:"deletion_time"`
+		Destroyed    bool   `json:"destroyed"`
+		Version      int    `json:"version"`
+	}
+
+	// KVv2UpdateMetadataOptions are the metadata fields to update. Fields left nil are not changed.
+	KVv2UpdateMetadataOptions struct {
+		MaxVersions        *int
+		CASRequired        *bool
+		DeleteVersionAfter *time.Duration
+		CustomMetadata     map[string]string
+	}
 )
 
 func (c *Client) KVv2() KVv2 {
 	return &kvv2Impl{
-		client:    c,
-		MountPath: DefaultKVv2MountPath,
+		client:     c,
+		MountPath:  DefaultKVv2MountPath,
+		CASRetries: DefaultKVv2CASRetries,
 	}
 }
 
-func (k *kvv2Impl) do(method, endpoint string, params map[string]interface{}, body interface{}) (*vaultResponse, error) {
-	return k.client.doV1(method, path.Join(k.MountPath, endpoint), params, body)
+func (k *kvv2Impl) do(ctx context.Context, method, endpoint string, params query, body interface{}) (*vaultResponse, error) {
+	return k.client.doV1(ctx, method, path.Join(k.MountPath, endpoint), params, body)
 }
 
 func (k *kvv2Impl) WithMountPath(path string) KVv2 {
@@ -75,9 +146,20 @@ func (k *kvv2Impl) WithMountPath(path string) KVv2 {
 	return &kCopy
 }
 
+// WithCASRetries sets how many times UpdateSecret retries after a check-and-set conflict.
+func (k *kvv2Impl) WithCASRetries(retries int) KVv2 {
+	kCopy := *k
+	kCopy.CASRetries = retries
+	return &kCopy
+}
+
 // curl command: `curl -X POST -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" -d '{"max_versions":5,"cas_required":false,"delete_version_after":"3h25m19s"}' http://127.0.0.1:8200/v1/secret/config`
 func (k *kvv2Impl) Configure(config *KVv2Config) error {
-	r, err := k.do(http.MethodPost, "config", nil, config)
+	return k.ConfigureCtx(context.Background(), config)
+}
+
+func (k *kvv2Impl) ConfigureCtx(ctx context.Context, config *KVv2Config) error {
+	r, err := k.do(ctx, http.MethodPost, "config", nil, config)
 	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
 		return err
 	}
@@ -86,7 +168,11 @@ func (k *kvv2Impl) Configure(config *KVv2Config) error {
 }
 
 func (k *kvv2Impl) ReadConfig() (*KVv2Config, error) {
-	r, err := k.do(http.MethodGet, "config", nil, nil)
+	return k.ReadConfigCtx(context.Background())
+}
+
+func (k *kvv2Impl) ReadConfigCtx(ctx context.Context) (*KVv2Config, error) {
+	r, err := k.do(ctx, http.MethodGet, "config", nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,8 +187,15 @@ func (k *kvv2Impl) ReadConfig() (*KVv2Config, error) {
 // vault command: `vault kv get -version={version} secret/{path}`
 // curl command: `curl -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" http://127.0.0.1:8200/v1/secret/data/{path}?version={version}`
 func (k *kvv2Impl) ReadSecretVersion(path string, version int) (*KVv2Secret, error) {
-	q := map[string]interface{}{"version": version}
-	r, err := k.do(http.MethodGet, "data/"+path, q, nil)
+	return k.ReadSecretVersionCtx(context.Background(), path, version)
+}
+
+func (k *kvv2Impl) ReadSecretVersionCtx(ctx context.Context, path string, version int) (*KVv2Secret, error) {
+	q := newQuery()
+	if version > 0 {
+		q.Set("version", version)
+	}
+	r, err := k.do(ctx, http.MethodGet, "data/"+path, q, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,20 +207,189 @@ func (k *kvv2Impl) ReadSecretVersion(path string, version int) (*KVv2Secret, err
 	return v, nil
 }
 
+// CreateOrUpdateSecret writes a new version of a secret and returns the metadata of the version written.
 // vault command: `vault kv put -cas=1 secret/mysecret mykey=myval
 // `curl -X PUT -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" -d '{"data":{"mykey":"myval"},"options":{"cas":1}}' http://127.0.0.1:8200/v1/secret/data/mysecret`
-func (k *kvv2Impl) CreateOrUpdateSecret(path string, data map[string]string, options *KVv2CreateOrUpdateSecretOptions) error {
-	body := kvv2CreateOrUpdateSecretRequest{Data: data, Options: *options}
-	r, err := k.do(http.MethodPut, "data/"+path, nil, body)
-	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
-		return err
+func (k *kvv2Impl) CreateOrUpdateSecret(path string, data map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	return k.CreateOrUpdateSecretCtx(context.Background(), path, data, options)
+}
+
+func (k *kvv2Impl) CreateOrUpdateSecretCtx(ctx context.Context, path string, data map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	body := kvv2CreateOrUpdateSecretRequest{Data: data}
+	if options != nil {
+		body.Options = *options
+	}
+	r, err := k.do(ctx, http.MethodPut, "data/"+path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	k.client.Logger.Trace(r)
+	return parseVersionMetadata(r)
+}
+
+// PatchSecret merges patch into the latest version of an existing secret, writing the result as a new version. Keys
+// with a nil value are removed and nested objects are merged, following JSON merge patch (RFC 7396). When the server
+// does not support PATCH, the secret is read, merged and written back with a check-and-set on the version read, so a
+// concurrent write fails the patch rather than being overwritten. options.CAS, if set, is honored in both cases.
+// vault command: `vault kv patch -cas=1 secret/{path} mykey=myval`
+func (k *kvv2Impl) PatchSecret(path string, patch map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	return k.PatchSecretCtx(context.Background(), path, patch, options)
+}
+
+func (k *kvv2Impl) PatchSecretCtx(ctx context.Context, path string, patch map[string]interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	body := kvv2CreateOrUpdateSecretRequest{Data: patch}
+	if options != nil {
+		body.Options = *options
+	}
+	r, err := k.do(withHeader(ctx, "Content-Type", "application/merge-patch+json"), http.MethodPatch, "data/"+path, nil, body)
+	if err == nil {
+		k.client.Logger.Trace(r)
+		return parseVersionMetadata(r)
+	}
+	if !errors.Is(err, &ErrUnknownStatusCode{StatusCode: http.StatusMethodNotAllowed}) {
+		return nil, err
+	}
+
+	k.client.Logger.Debug("PATCH not supported, falling back to read and check-and-set write")
+	current, err := k.ReadSecretVersionCtx(ctx, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	cas := current.Metadata.Version
+	if options != nil && options.CAS != 0 {
+		cas = options.CAS
+	}
+	data := mergePatch(current.Data, patch)
+	return k.CreateOrUpdateSecretCtx(ctx, path, data, &KVv2CreateOrUpdateSecretOptions{CAS: cas})
+}
+
+// UpdateSecret applies update to the latest version of a secret and writes the result with a check-and-set on that
+// version, so concurrent writers cannot overwrite each other. On a check-and-set conflict the secret is read again
+// and update is called with the new data, up to CASRetries times. It returns the version written.
+func (k *kvv2Impl) UpdateSecret(path string, update KVv2UpdateFunc) (int, error) {
+	return k.UpdateSecretCtx(context.Background(), path, update)
+}
+
+func (k *kvv2Impl) UpdateSecretCtx(ctx context.Context, path string, update KVv2UpdateFunc) (int, error) {
+	for attempt := 0; ; attempt++ {
+		version, current, err := k.readLatest(ctx, path)
+		if err != nil {
+			return 0, err
+		}
+		data, err := update(current)
+		if err != nil {
+			return 0, err
+		}
+		written, err := k.writeSecretCAS(ctx, path, data, version)
+		if err == nil {
+			return written.Version, nil
+		}
+		if !isCASMismatch(err) {
+			return 0, err
+		}
+		if attempt >= k.CASRetries {
+			return 0, fmt.Errorf("updating secret %s: giving up after %d check-and-set conflicts: %w", path, attempt+1, err)
+		}
+		k.client.Logger.Debug("retrying update after check-and-set conflict:", err)
+	}
+}
+
+// readLatest returns the current version of a secret from its metadata along with that version's data. Both are
+// zero if the secret does not exist, and the data is nil if the version is deleted or destroyed.
+func (k *kvv2Impl) readLatest(ctx context.Context, path string) (int, map[string]interface{}, error) {
+	metadata, err := k.ReadSecretMetadataCtx(ctx, path)
+	if errors.Is(err, &ErrInvalidPath{}) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if metadata.CurrentVersion == 0 {
+		return 0, nil, nil
+	}
+	secret, err := k.ReadSecretVersionCtx(ctx, path, metadata.CurrentVersion)
+	if errors.Is(err, &ErrInvalidPath{}) {
+		return metadata.CurrentVersion, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return metadata.CurrentVersion, secret.Data, nil
+}
+
+// writeSecretCAS writes a new version of a secret only if its current version is cas, where 0 means the secret must
+// not exist yet.
+func (k *kvv2Impl) writeSecretCAS(ctx context.Context, path string, data map[string]interface{}, cas int) (*KVv2SecretVersionMetadata, error) {
+	body := map[string]interface{}{
+		"data":    data,
+		"options": map[string]interface{}{"cas": cas},
+	}
+	r, err := k.do(ctx, http.MethodPut, "data/"+path, nil, body)
+	if err != nil {
+		return nil, err
 	}
 	k.client.Logger.Trace(r)
-	return err
+	return parseVersionMetadata(r)
 }
 
+// parseVersionMetadata decodes the metadata of the version created by a write.
+func parseVersionMetadata(r *vaultResponse) (*KVv2SecretVersionMetadata, error) {
+	v := new(KVv2SecretVersionMetadata)
+	if err := typeConvert(r.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// isCASMismatch reports whether err is Vault rejecting a write because its check-and-set version did not match.
+func isCASMismatch(err error) bool {
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, e := range respErr.Errors {
+		if strings.Contains(e, "check-and-set") {
+			return true
+		}
+	}
+	return false
+}
+
+// mergePatch applies a JSON merge patch (RFC 7396) to a copy of target.
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(target))
+	for key, value := range target {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		if patchObj, ok := value.(map[string]interface{}); ok {
+			targetObj, _ := merged[key].(map[string]interface{})
+			merged[key] = mergePatch(targetObj, patchObj)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// Decode converts the secret's data into the struct pointed to by v using its JSON field tags.
+func (s *KVv2Secret) Decode(v interface{}) error {
+	return typeConvert(s.Data, v)
+}
+
+// DeleteLatestSecretVersion soft-deletes the latest version of a secret. Vault responds with no content, so unlike
+// writes no version metadata is returned; use ReadSecretMetadata to inspect the deleted version.
+// vault command: `vault kv delete secret/{path}`
 func (k *kvv2Impl) DeleteLatestSecretVersion(path string) error {
-	r, err := k.do(http.MethodDelete, "data/"+path, nil, nil)
+	return k.DeleteLatestSecretVersionCtx(context.Background(), path)
+}
+
+func (k *kvv2Impl) DeleteLatestSecretVersionCtx(ctx context.Context, path string) error {
+	r, err := k.do(ctx, http.MethodDelete, "data/"+path, nil, nil)
 	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
 		return err
 	}
@@ -137,8 +399,12 @@ func (k *kvv2Impl) DeleteLatestSecretVersion(path string) error {
 
 // curl -X POST -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" -d '{"versions":[1,2]}' https://127.0.0.1:8200/v1/secret/data/my-secret
 func (k *kvv2Impl) DeleteSecretVersions(path string, versions []int) error {
+	return k.DeleteSecretVersionsCtx(context.Background(), path, versions)
+}
+
+func (k *kvv2Impl) DeleteSecretVersionsCtx(ctx context.Context, path string, versions []int) error {
 	body := map[string]interface{}{"versions": versions}
-	r, err := k.do(http.MethodPost, "delete/"+path, nil, body)
+	r, err := k.do(ctx, http.MethodPost, "delete/"+path, nil, body)
 	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
 		return err
 	}
@@ -148,8 +414,12 @@ func (k *kvv2Impl) DeleteSecretVersions(path string, versions []int) error {
 
 // curl -X POST -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" -d '{"versions":[1,2]}' https://127.0.0.1:8200/v1/secret/undelete/my-secret
 func (k *kvv2Impl) UndeleteSecretVersions(path string, versions []int) error {
+	return k.UndeleteSecretVersionsCtx(context.Background(), path, versions)
+}
+
+func (k *kvv2Impl) UndeleteSecretVersionsCtx(ctx context.Context, path string, versions []int) error {
 	body := map[string]interface{}{"versions": versions}
-	r, err := k.do(http.MethodPost, "undelete/"+path, nil, body)
+	r, err := k.do(ctx, http.MethodPost, "undelete/"+path, nil, body)
 	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
 		return err
 	}
@@ -159,8 +429,12 @@ func (k *kvv2Impl) UndeleteSecretVersions(path string, versions []int) error {
 
 // curl -X POST -H "X-Vault-Request: true" -H "X-Vault-Token: $(vault print token)" -d '{"versions":[1,2]}' https://127.0.0.1:8200/v1/secret/destroy/my-secret
 func (k *kvv2Impl) DestroySecretVersions(path string, versions []int) error {
+	return k.DestroySecretVersionsCtx(context.Background(), path, versions)
+}
+
+func (k *kvv2Impl) DestroySecretVersionsCtx(ctx context.Context, path string, versions []int) error {
 	body := map[string]interface{}{"versions": versions}
-	r, err := k.do(http.MethodPost, "destroy/"+path, nil, body)
+	r, err := k.do(ctx, http.MethodPost, "destroy/"+path, nil, body)
 	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
 		return err
 	}
@@ -169,28 +443,83 @@ func (k *kvv2Impl) DestroySecretVersions(path string, versions []int) error {
 }
 
 func (k *kvv2Impl) ListSecrets(path string) ([]string, error) {
-	r, err := k.do(http.MethodGet, "metadata/"+path, nil, nil)
-	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+	return k.ListSecretsCtx(context.Background(), path)
+}
+
+func (k *kvv2Impl) ListSecretsCtx(ctx context.Context, path string) ([]string, error) {
+	r, err := k.do(ctx, http.MethodGet, "metadata/"+path, newQuery().List(), nil)
+	if errors.Is(err, &ErrSuccessNoData{}) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	k.client.Logger.Trace(r)
+	return parseKeys(r)
+}
+
+// vault command: `vault kv metadata get secret/{path}`
+func (k *kvv2Impl) ReadSecretMetadata(path string) (*KVv2Metadata, error) {
+	return k.ReadSecretMetadataCtx(context.Background(), path)
+}
+
+func (k *kvv2Impl) ReadSecretMetadataCtx(ctx context.Context, path string) (*KVv2Metadata, error) {
+	r, err := k.do(ctx, http.MethodGet, "metadata/"+path, nil, nil)
+	if err != nil {
 		return nil, err
 	}
 	k.client.Logger.Trace(r)
-	data := new(struct {
-		Keys []string `json:"keys"`
-	})
-	if err := typeConvert(r.Data, data); err != nil {
+	v := new(KVv2Metadata)
+	if err := typeConvert(r.Data, v); err != nil {
 		return nil, err
 	}
-	return data.Keys, nil
+	for version, m := range v.Versions {
+		m.Version = version
+		v.Versions[version] = m
+	}
+	return v, nil
 }
 
-func (k *kvv2Impl) ReadSecretMetadata(path string) error {
-	panic("implement me")
+// vault command: `vault kv metadata put -max-versions=5 -cas-required=true -delete-version-after=3h25m19s -custom-metadata=foo=bar secret/{path}`
+func (k *kvv2Impl) UpdateMetadata(path string, options *KVv2UpdateMetadataOptions) error {
+	return k.UpdateMetadataCtx(context.Background(), path, options)
 }
 
-func (k *kvv2Impl) UpdateMetadata(path string, maxVersions int, casRequired bool, deleteVersionAfter time.Duration) error {
-	panic("implement me")
+func (k *kvv2Impl) UpdateMetadataCtx(ctx context.Context, path string, options *KVv2UpdateMetadataOptions) error {
+	body := map[string]interface{}{}
+	if options != nil {
+		if options.MaxVersions != nil {
+			body["max_versions"] = *options.MaxVersions
+		}
+		if options.CASRequired != nil {
+			body["cas_required"] = *options.CASRequired
+		}
+		if options.DeleteVersionAfter != nil {
+			body["delete_version_after"] = options.DeleteVersionAfter.String()
+		}
+		if options.CustomMetadata != nil {
+			body["custom_metadata"] = options.CustomMetadata
+		}
+	}
+	r, err := k.do(ctx, http.MethodPost, "metadata/"+path, nil, body)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	k.client.Logger.Trace(r)
+	return nil
 }
 
+// DeleteMetadataAndAllVersions permanently deletes the metadata and all versions of a secret.
+// vault command: `vault kv metadata delete secret/{path}`
 func (k *kvv2Impl) DeleteMetadataAndAllVersions(path string) error {
-	panic("implement me")
+	return k.DeleteMetadataAndAllVersionsCtx(context.Background(), path)
+}
+
+func (k *kvv2Impl) DeleteMetadataAndAllVersionsCtx(ctx context.Context, path string) error {
+	r, err := k.do(ctx, http.MethodDelete, "metadata/"+path, nil, nil)
+	if err != nil && !errors.Is(err, &ErrSuccessNoData{}) {
+		return err
+	}
+	k.client.Logger.Trace(r)
+	return nil
 }
@@ -0,0 +1,312 @@
+package govault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Conflict policies for ImportMount, deciding what happens to secrets that already exist in the destination.
+const (
+	// KVv2ConflictSkip leaves existing secrets untouched.
+	KVv2ConflictSkip KVv2ConflictPolicy = iota
+
+	// KVv2ConflictOverwrite writes the imported versions on top of existing secrets. Writes fail on paths that
+	// require check-and-set; use KVv2ConflictCAS for those.
+	KVv2ConflictOverwrite
+
+	// KVv2ConflictCAS writes the imported versions with a check-and-set on the destination version read before
+	// writing, so secrets created or changed by someone else during the import are reported as conflicts instead of
+	// being overwritten. A secret changed between two versions of the same record is left partly imported, and
+	// ImportMount returns an error for it rather than a conflict.
+	KVv2ConflictCAS
+)
+
+type (
+	KVv2ConflictPolicy int
+
+	// KVv2ExportRecord is a single secret in an export stream. Path is relative to the exported root. Versions
+	// holds the latest version, or every version in ascending order when exporting all versions; deleted and
+	// destroyed versions have no data.
+	KVv2ExportRecord struct {
+		Path     string        `json:"path"`
+		Metadata *KVv2Metadata `json:"metadata,omitempty"`
+		Versions []KVv2Secret  `json:"versions"`
+	}
+
+	KVv2ExportOptions struct {
+		// AllVersions exports every version of each secret instead of only the latest.
+		AllVersions bool
+
+		// Metadata exports the metadata of each secret, ie max_versions and custom_metadata.
+		Metadata bool
+
+		// Walk selects the secrets to export, see WalkSecrets.
+		Walk *KVv2WalkOptions
+
+		// Progress, if set, is called after each secret is exported.
+		Progress func(path string)
+	}
+
+	KVv2ImportOptions struct {
+		// Conflict decides what happens to secrets that already exist. Defaults to KVv2ConflictSkip.
+		Conflict KVv2ConflictPolicy
+
+		// Metadata applies the exported metadata of each secret after writing it.
+		Metadata bool
+
+		// DryRun reports what would be imported without writing anything.
+		DryRun bool
+
+		// Progress, if set, is called after each secret is imported, skipped or found in conflict.
+		Progress func(path string)
+	}
+
+	// KVv2ImportResult lists the destination paths of the secrets handled by ImportMount.
+	KVv2ImportResult struct {
+		// Written are the secrets imported, or that would be in a dry run.
+		Written []string
+
+		// Skipped are the existing secrets left untouched by KVv2ConflictSkip, and the records with no version to
+		// write, ie whose exported versions were all deleted or destroyed.
+		Skipped []string
+
+		// Conflicts are the secrets changed during the import that KVv2ConflictCAS did not overwrite.
+		Conflicts []string
+	}
+)
+
+// ExportMount writes the secrets under root to w as a stream of newline-delimited JSON KVv2ExportRecords, which
+// ImportMount replays into another mount or cluster. It returns the number of secrets exported. There is no YAML
+// encoding, to keep the module free of dependencies, but each line is a JSON document and so also a YAML one.
+func (k *kvv2Impl) ExportMount(w io.Writer, root string, options *KVv2ExportOptions) (int, error) {
+	return k.ExportMountCtx(context.Background(), w, root, options)
+}
+
+func (k *kvv2Impl) ExportMountCtx(ctx context.Context, w io.Writer, root string, options *KVv2ExportOptions) (int, error) {
+	opts := KVv2ExportOptions{}
+	if options != nil {
+		opts = *options
+	}
+	root = strings.Trim(root, "/")
+	paths, err := k.ListSecretsRecursiveCtx(ctx, root, opts.Walk)
+	if err != nil {
+		return 0, err
+	}
+
+	enc := json.NewEncoder(w)
+	exported := 0
+	for _, p := range paths {
+		record, err := k.exportSecret(ctx, p, opts)
+		if err != nil {
+			return exported, fmt.Errorf("exporting %s: %w", p, err)
+		}
+		if record == nil {
+			continue
+		}
+		record.Path = strings.TrimPrefix(strings.TrimPrefix(p, root), "/")
+		if err := enc.Encode(record); err != nil {
+			return exported, err
+		}
+		exported++
+		if opts.Progress != nil {
+			opts.Progress(p)
+		}
+	}
+	return exported, nil
+}
+
+// exportSecret reads the versions and metadata of a secret to export. It returns nil if there is nothing to export,
+// ie the latest version is deleted and only the latest version is exported.
+func (k *kvv2Impl) exportSecret(ctx context.Context, p string, opts KVv2ExportOptions) (*KVv2ExportRecord, error) {
+	record := &KVv2ExportRecord{}
+	var metadata *KVv2Metadata
+	if opts.AllVersions || opts.Metadata {
+		var err error
+		if metadata, err = k.ReadSecretMetadataCtx(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Metadata {
+		record.Metadata = metadata
+	}
+
+	if !opts.AllVersions {
+		secret, err := k.ReadSecretVersionCtx(ctx, p, 0)
+		if errors.Is(err, &ErrInvalidPath{}) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record.Versions = []KVv2Secret{*secret}
+		return record, nil
+	}
+
+	versions := make([]int, 0, len(metadata.Versions))
+	for version := range metadata.Versions {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	for _, version := range versions {
+		vm := metadata.Versions[version]
+		if vm.Destroyed {
+			record.Versions = append(record.Versions, KVv2Secret{Metadata: vm})
+			continue
+		}
+		secret, err := k.ReadSecretVersionCtx(ctx, p, version)
+		if errors.Is(err, &ErrInvalidPath{}) {
+			record.Versions = append(record.Versions, KVv2Secret{Metadata: vm})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		record.Versions = append(record.Versions, *secret)
+	}
+	return record, nil
+}
+
+// ImportMount replays a stream written by ExportMount into the mount under root. Each version with data is written
+// as a new version, so version numbers are not preserved, and deleted or destroyed versions are skipped. Records are
+// imported as they are read, so an error leaves the records before it imported. Records whose path is absolute or
+// contains ".." are rejected, so a stream cannot write outside of root.
+func (k *kvv2Impl) ImportMount(r io.Reader, root string, options *KVv2ImportOptions) (*KVv2ImportResult, error) {
+	return k.ImportMountCtx(context.Background(), r, root, options)
+}
+
+func (k *kvv2Impl) ImportMountCtx(ctx context.Context, r io.Reader, root string, options *KVv2ImportOptions) (*KVv2ImportResult, error) {
+	opts := KVv2ImportOptions{}
+	if options != nil {
+		opts = *options
+	}
+	root = strings.Trim(root, "/")
+	result := &KVv2ImportResult{}
+
+	dec := json.NewDecoder(r)
+	for {
+		var record KVv2ExportRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, fmt.Errorf("reading export: %w", err)
+		}
+		p, err := importPath(root, record.Path)
+		if err != nil {
+			return result, fmt.Errorf("reading export: %w", err)
+		}
+		if err := k.importSecret(ctx, p, &record, opts, result); err != nil {
+			return result, fmt.Errorf("importing %s: %w", p, err)
+		}
+		if opts.Progress != nil {
+			opts.Progress(p)
+		}
+	}
+}
+
+// importPath returns the destination of a record path under root, rejecting paths that would write outside of it.
+func importPath(root, p string) (string, error) {
+	if p == "" {
+		return "", errors.New("record has no path")
+	}
+	if strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("record path %q is absolute", p)
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("record path %q contains %q", p, segment)
+		}
+	}
+	dest := path.Join(root, p)
+	if dest == "." || dest == root || root != "" && !strings.HasPrefix(dest, root+"/") {
+		return "", fmt.Errorf("record path %q is not under %q", p, root)
+	}
+	return dest, nil
+}
+
+// importSecret writes a record to p according to the conflict policy, adding p to result.
+func (k *kvv2Impl) importSecret(ctx context.Context, p string, record *KVv2ExportRecord, opts KVv2ImportOptions, result *KVv2ImportResult) error {
+	if !record.hasData() {
+		result.Skipped = append(result.Skipped, p)
+		return nil
+	}
+
+	current := 0
+	metadata, err := k.ReadSecretMetadataCtx(ctx, p)
+	if err != nil && !errors.Is(err, &ErrInvalidPath{}) {
+		return err
+	}
+	if metadata != nil {
+		current = metadata.CurrentVersion
+	}
+	if current > 0 && opts.Conflict == KVv2ConflictSkip {
+		result.Skipped = append(result.Skipped, p)
+		return nil
+	}
+	if opts.DryRun {
+		result.Written = append(result.Written, p)
+		return nil
+	}
+
+	written := 0
+	for _, version := range record.Versions {
+		if version.Data == nil {
+			continue
+		}
+		if opts.Conflict != KVv2ConflictCAS {
+			if _, err := k.CreateOrUpdateSecretCtx(ctx, p, version.Data, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		vm, err := k.writeSecretCAS(ctx, p, version.Data, current)
+		if isCASMismatch(err) && written == 0 {
+			result.Conflicts = append(result.Conflicts, p)
+			return nil
+		}
+		if isCASMismatch(err) {
+			return fmt.Errorf("changed concurrently after %d of its versions were imported: %w", written, err)
+		}
+		if err != nil {
+			return err
+		}
+		current = vm.Version
+		written++
+	}
+
+	if opts.Metadata && record.Metadata != nil {
+		m := record.Metadata
+		update := &KVv2UpdateMetadataOptions{
+			MaxVersions:    &m.MaxVersions,
+			CASRequired:    &m.CASRequired,
+			CustomMetadata: m.CustomMetadata,
+		}
+		if m.DeleteVersionAfter != "" {
+			update.DeleteVersionAfter = new(time.Duration)
+			if *update.DeleteVersionAfter, err = time.ParseDuration(m.DeleteVersionAfter); err != nil {
+				return err
+			}
+		}
+		if err := k.UpdateMetadataCtx(ctx, p, update); err != nil {
+			return err
+		}
+	}
+	result.Written = append(result.Written, p)
+	return nil
+}
+
+// hasData reports whether the record has a version to import.
+func (r *KVv2ExportRecord) hasData() bool {
+	for _, version := range r.Versions {
+		if version.Data != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package govault
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func Test_kvv2Impl_ExportMount(t *testing.T) {
+	c, src := newKVv2MemServer(t)
+	src.put("app/db", map[string]interface{}{"password": "v1"})
+	src.put("app/db", map[string]interface{}{"password": "v2"})
+	src.put("app/api/key", map[string]interface{}{"key": "abc"})
+	src.put("app/gone", map[string]interface{}{"key": "old"})
+	src.deleted["app/gone"] = map[int]bool{1: true}
+	src.put("other", map[string]interface{}{"key": "x"})
+	src.metadata["app/db"] = map[string]interface{}{"max_versions": 3, "custom_metadata": map[string]string{"owner": "a"}}
+
+	tests := []struct {
+		name         string
+		options      *KVv2ExportOptions
+		wantPaths    []string
+		wantVersions map[string]int
+	}{
+		{
+			name:         "Latest",
+			wantPaths:    []string{"api/key", "db"},
+			wantVersions: map[string]int{"api/key": 1, "db": 1},
+		},
+		{
+			name:         "AllVersions",
+			options:      &KVv2ExportOptions{AllVersions: true, Metadata: true},
+			wantPaths:    []string{"api/key", "db", "gone"},
+			wantVersions: map[string]int{"api/key": 1, "db": 2, "gone": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var progress []string
+			opts := tt.options
+			if opts == nil {
+				opts = &KVv2ExportOptions{}
+			}
+			opts.Progress = func(p string) { progress = append(progress, p) }
+			n, err := c.KVv2().ExportMount(&buf, "app", opts)
+			if err != nil {
+				t.Fatalf("ExportMount() error = %v", err)
+			}
+			if n != len(tt.wantPaths) || len(progress) != n {
+				t.Errorf("ExportMount() = %d with %d progress calls, want %d", n, len(progress), len(tt.wantPaths))
+			}
+
+			var gotPaths []string
+			dec := json.NewDecoder(&buf)
+			for dec.More() {
+				var record KVv2ExportRecord
+				if err := dec.Decode(&record); err != nil {
+					t.Fatal(err)
+				}
+				gotPaths = append(gotPaths, record.Path)
+				if got := len(record.Versions); got != tt.wantVersions[record.Path] {
+					t.Errorf("ExportMount() %s has %d versions, want %d", record.Path, got, tt.wantVersions[record.Path])
+				}
+				if record.Path == "gone" && record.Versions[0].Data != nil {
+					t.Errorf("ExportMount() exported data of a deleted version")
+				}
+				if record.Path == "db" && opts.Metadata && record.Metadata.MaxVersions != 3 {
+					t.Errorf("ExportMount() metadata = %+v", record.Metadata)
+				}
+			}
+			sort.Strings(gotPaths)
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("ExportMount() paths = %v, want %v", gotPaths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_ImportMount(t *testing.T) {
+	export := func(t *testing.T) *bytes.Buffer {
+		c, src := newKVv2MemServer(t)
+		src.put("db", map[string]interface{}{"password": "v1"})
+		src.put("db", map[string]interface{}{"password": "v2"})
+		src.put("api/key", map[string]interface{}{"key": "abc"})
+		src.metadata["db"] = map[string]interface{}{"max_versions": 3, "delete_version_after": "1h0m0s"}
+		var buf bytes.Buffer
+		if _, err := c.KVv2().ExportMount(&buf, "", &KVv2ExportOptions{AllVersions: true, Metadata: true}); err != nil {
+			t.Fatalf("ExportMount() error = %v", err)
+		}
+		return &buf
+	}
+
+	tests := []struct {
+		name        string
+		options     *KVv2ImportOptions
+		want        *KVv2ImportResult
+		wantDB      []string
+		wantAPIKeys int
+	}{
+		{
+			name:        "Skip",
+			want:        &KVv2ImportResult{Written: []string{"imported/api/key"}, Skipped: []string{"imported/db"}},
+			wantDB:      []string{"existing"},
+			wantAPIKeys: 1,
+		},
+		{
+			name:        "Overwrite",
+			options:     &KVv2ImportOptions{Conflict: KVv2ConflictOverwrite, Metadata: true},
+			want:        &KVv2ImportResult{Written: []string{"imported/api/key", "imported/db"}},
+			wantDB:      []string{"existing", "v1", "v2"},
+			wantAPIKeys: 1,
+		},
+		{
+			name:        "CAS",
+			options:     &KVv2ImportOptions{Conflict: KVv2ConflictCAS},
+			want:        &KVv2ImportResult{Written: []string{"imported/api/key", "imported/db"}},
+			wantDB:      []string{"existing", "v1", "v2"},
+			wantAPIKeys: 1,
+		},
+		{
+			name:    "DryRun",
+			options: &KVv2ImportOptions{Conflict: KVv2ConflictOverwrite, DryRun: true},
+			want:    &KVv2ImportResult{Written: []string{"imported/api/key", "imported/db"}},
+			wantDB:  []string{"existing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, dst := newKVv2MemServer(t)
+			dst.put("imported/db", map[string]interface{}{"password": "existing"})
+
+			got, err := c.KVv2().ImportMount(export(t), "/imported/", tt.options)
+			if err != nil {
+				t.Fatalf("ImportMount() error = %v", err)
+			}
+			sort.Strings(got.Written)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportMount() got = %+v, want %+v", got, tt.want)
+			}
+
+			var gotDB []string
+			for _, v := range dst.versions["imported/db"] {
+				gotDB = append(gotDB, v["password"].(string))
+			}
+			if !reflect.DeepEqual(gotDB, tt.wantDB) {
+				t.Errorf("ImportMount() db versions = %v, want %v", gotDB, tt.wantDB)
+			}
+			if got := len(dst.versions["imported/api/key"]); got != tt.wantAPIKeys {
+				t.Errorf("ImportMount() api/key versions = %d, want %d", got, tt.wantAPIKeys)
+			}
+			if tt.options != nil && tt.options.Metadata && dst.metadata["imported/db"]["delete_version_after"] != "1h0m0s" {
+				t.Errorf("ImportMount() metadata = %v", dst.metadata["imported/db"])
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_ImportMount_Conflict(t *testing.T) {
+	client, dst := newKVv2MemServer(t)
+	stream := strings.NewReader(`{"path":"db","versions":[{"data":{"password":"new"},"metadata":{"version":1}}]}`)
+	// another writer creates the secret between the import reading and writing it
+	readMetadata := false
+	dst.before = func(r *http.Request) {
+		if r.Method == http.MethodPut && readMetadata {
+			dst.put("db", map[string]interface{}{"password": "concurrent"})
+		}
+		if strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/") {
+			readMetadata = true
+		}
+	}
+
+	got, err := client.KVv2().ImportMount(stream, "", &KVv2ImportOptions{Conflict: KVv2ConflictCAS})
+	if err != nil {
+		t.Fatalf("ImportMount() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Conflicts, []string{"db"}) || len(got.Written) != 0 {
+		t.Errorf("ImportMount() got = %+v, want a conflict on db", got)
+	}
+	if len(dst.versions["db"]) != 1 {
+		t.Errorf("ImportMount() overwrote a concurrent write: %v", dst.versions["db"])
+	}
+}
+
+func Test_kvv2Impl_ImportMount_Invalid(t *testing.T) {
+	c, s := newKVv2MemServer(t)
+	for _, stream := range []string{
+		`{"path":""}`,
+		`{"path":`,
+		`{"path":"../../other-team/db","versions":[{"data":{"k":"v"}}]}`,
+		`{"path":"app/../../db","versions":[{"data":{"k":"v"}}]}`,
+		`{"path":"/abs","versions":[{"data":{"k":"v"}}]}`,
+		`{"path":".","versions":[{"data":{"k":"v"}}]}`,
+	} {
+		if _, err := c.KVv2().ImportMount(strings.NewReader(stream), "team-a", nil); err == nil {
+			t.Errorf("ImportMount(%q) error = nil, want error", stream)
+		}
+	}
+	if len(s.versions) != 0 {
+		t.Errorf("ImportMount() wrote %v, want nothing", s.versions)
+	}
+}
+
+func Test_kvv2Impl_ImportMount_NoData(t *testing.T) {
+	c, dst := newKVv2MemServer(t)
+	stream := strings.NewReader(`{"path":"gone","versions":[{"metadata":{"version":1,"destroyed":true}}]}`)
+	got, err := c.KVv2().ImportMount(stream, "", nil)
+	if err != nil {
+		t.Fatalf("ImportMount() error = %v", err)
+	}
+	want := &KVv2ImportResult{Skipped: []string{"gone"}}
+	if !reflect.DeepEqual(got, want) || len(dst.versions) != 0 {
+		t.Errorf("ImportMount() got = %+v with versions %v, want %+v", got, dst.versions, want)
+	}
+}
+
+func Test_kvv2Impl_ImportMount_PartialConflict(t *testing.T) {
+	client, dst := newKVv2MemServer(t)
+	stream := strings.NewReader(`{"path":"db","versions":[{"data":{"password":"v1"}},{"data":{"password":"v2"}}]}`)
+	// another writer updates the secret between the first and second imported versions
+	puts := 0
+	dst.before = func(r *http.Request) {
+		if r.Method == http.MethodPut {
+			if puts++; puts == 2 {
+				dst.put("db", map[string]interface{}{"password": "concurrent"})
+			}
+		}
+	}
+
+	got, err := client.KVv2().ImportMount(stream, "", &KVv2ImportOptions{Conflict: KVv2ConflictCAS})
+	if err == nil || !isCASMismatch(err) {
+		t.Fatalf("ImportMount() error = %v, want a check-and-set conflict", err)
+	}
+	if len(got.Conflicts) != 0 || len(got.Written) != 0 {
+		t.Errorf("ImportMount() got = %+v, want the partial import reported as an error", got)
+	}
+	if len(dst.versions["db"]) != 2 {
+		t.Errorf("ImportMount() db versions = %v, want v1 and the concurrent write", dst.versions["db"])
+	}
+}
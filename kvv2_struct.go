@@ -0,0 +1,244 @@
+package govault
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Secrets are mapped to structs with `vault:"key"` field tags, ie:
+//
+//	type Database struct {
+//		Username string        `vault:"username,required"`
+//		Password []byte        `vault:"password"`
+//		Timeout  time.Duration `vault:"timeout,omitempty"`
+//		Pool     struct {
+//			Size int `vault:"size"`
+//		} `vault:"pool"`
+//	}
+//
+// Fields without a tag use the field name as key, and fields tagged `vault:"-"` are ignored. The "required" option
+// fails reading a secret without the key and writing an empty string or a nil pointer, slice or map; other zero values
+// such as 0 or false are written. "omitempty" leaves out zero values on write.
+// Nested structs and pointers to structs map to JSON objects, time.Duration to a duration string such as "1h30m0s"
+// (numbers are read as seconds) and []byte to a base64 string. All other types are converted through their JSON
+// encoding.
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	bytesType    = reflect.TypeOf([]byte(nil))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+type secretField struct {
+	key       string
+	omitEmpty bool
+	required  bool
+	index     int
+}
+
+// ReadSecretInto reads the latest version of a secret into the struct pointed to by v, see the `vault` struct tag.
+func (k *kvv2Impl) ReadSecretInto(path string, v interface{}) error {
+	return k.ReadSecretIntoCtx(context.Background(), path, v)
+}
+
+func (k *kvv2Impl) ReadSecretIntoCtx(ctx context.Context, path string, v interface{}) error {
+	secret, err := k.ReadSecretVersionCtx(ctx, path, 0)
+	if err != nil {
+		return err
+	}
+	return unmarshalSecret(secret.Data, v)
+}
+
+// WriteSecretFrom writes the struct v as a new version of a secret, see the `vault` struct tag.
+func (k *kvv2Impl) WriteSecretFrom(path string, v interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	return k.WriteSecretFromCtx(context.Background(), path, v, options)
+}
+
+func (k *kvv2Impl) WriteSecretFromCtx(ctx context.Context, path string, v interface{}, options *KVv2CreateOrUpdateSecretOptions) (*KVv2SecretVersionMetadata, error) {
+	data, err := marshalSecret(v)
+	if err != nil {
+		return nil, err
+	}
+	return k.CreateOrUpdateSecretCtx(ctx, path, data, options)
+}
+
+// marshalSecret converts a struct or pointer to struct into secret data.
+func marshalSecret(v interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("cannot marshal nil secret")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal secret from %T, want a struct", v)
+	}
+	data := map[string]interface{}{}
+	if err := marshalStruct(rv, data, ""); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func marshalStruct(rv reflect.Value, data map[string]interface{}, prefix string) error {
+	for _, f := range secretFields(rv.Type()) {
+		fv := rv.Field(f.index)
+		if f.required && isEmptyValue(fv) {
+			return fmt.Errorf("required secret key %q is empty", prefix+f.key)
+		}
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		value, err := marshalValue(fv, prefix+f.key)
+		if err != nil {
+			return err
+		}
+		data[f.key] = value
+	}
+	return nil
+}
+
+func marshalValue(fv reflect.Value, key string) (interface{}, error) {
+	switch {
+	case fv.Type() == durationType:
+		return time.Duration(fv.Int()).String(), nil
+	case fv.Type() == bytesType:
+		if fv.IsNil() {
+			return nil, nil
+		}
+		return base64.StdEncoding.EncodeToString(fv.Bytes()), nil
+	case fv.Kind() == reflect.Ptr:
+		if fv.IsNil() {
+			return nil, nil
+		}
+		return marshalValue(fv.Elem(), key)
+	case fv.Kind() == reflect.Struct && fv.Type() != timeType:
+		nested := map[string]interface{}{}
+		if err := marshalStruct(fv, nested, key+"."); err != nil {
+			return nil, err
+		}
+		return nested, nil
+	}
+	return fv.Interface(), nil
+}
+
+// isEmptyValue reports whether fv holds no value for a required key, ie an empty string or a nil pointer, slice or
+// map.
+func isEmptyValue(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.Len() == 0
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return fv.IsNil()
+	}
+	return false
+}
+
+// unmarshalSecret converts secret data into the struct pointed to by v.
+func unmarshalSecret(data map[string]interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal secret into %T, want a non-nil pointer to a struct", v)
+	}
+	return unmarshalStruct(data, rv.Elem(), "")
+}
+
+func unmarshalStruct(data map[string]interface{}, rv reflect.Value, prefix string) error {
+	for _, f := range secretFields(rv.Type()) {
+		value, ok := data[f.key]
+		if !ok || value == nil {
+			if f.required {
+				return fmt.Errorf("required secret key %q is missing", prefix+f.key)
+			}
+			continue
+		}
+		if err := unmarshalValue(value, rv.Field(f.index), prefix+f.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unmarshalValue(value interface{}, fv reflect.Value, key string) error {
+	switch {
+	case fv.Type() == durationType:
+		var d time.Duration
+		switch value := value.(type) {
+		case string:
+			var err error
+			if d, err = time.ParseDuration(value); err != nil {
+				return fmt.Errorf("secret key %q: %w", key, err)
+			}
+		case float64:
+			d = time.Duration(value * float64(time.Second))
+		default:
+			return fmt.Errorf("secret key %q: cannot convert %T to a duration", key, value)
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case fv.Type() == bytesType:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("secret key %q: cannot convert %T to base64 bytes", key, value)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("secret key %q: %w", key, err)
+		}
+		fv.SetBytes(b)
+		return nil
+	case fv.Kind() == reflect.Ptr:
+		elem := reflect.New(fv.Type().Elem())
+		if err := unmarshalValue(value, elem.Elem(), key); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	case fv.Kind() == reflect.Struct && fv.Type() != timeType:
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("secret key %q: cannot convert %T to a struct", key, value)
+		}
+		return unmarshalStruct(nested, fv, key+".")
+	}
+	if err := typeConvert(value, fv.Addr().Interface()); err != nil {
+		return fmt.Errorf("secret key %q: %w", key, err)
+	}
+	return nil
+}
+
+// secretFields returns the exported fields of t along with their parsed `vault` tags.
+func secretFields(t reflect.Type) []secretField {
+	var fields []secretField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("vault")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := secretField{key: parts[0], index: i}
+		if f.key == "" {
+			f.key = sf.Name
+		}
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "required":
+				f.required = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
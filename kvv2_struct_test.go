@@ -0,0 +1,192 @@
+package govault
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testDatabaseSecret struct {
+	Username string        `vault:"username,required"`
+	Password []byte        `vault:"password"`
+	Port     int           `vault:"port"`
+	Timeout  time.Duration `vault:"timeout,omitempty"`
+	Replica  *string       `vault:"replica,omitempty"`
+	Pool     struct {
+		Size int  `vault:"size"`
+		Warm bool `vault:"warm,omitempty"`
+	} `vault:"pool"`
+	Tags     []string
+	Internal string `vault:"-"`
+}
+
+func Test_marshalSecret(t *testing.T) {
+	replica := "db-2"
+	secret := testDatabaseSecret{
+		Username: "admin",
+		Password: []byte("hunter2"),
+		Port:     5432,
+		Timeout:  90 * time.Second,
+		Replica:  &replica,
+		Tags:     []string{"a"},
+		Internal: "ignored",
+	}
+	secret.Pool.Size = 10
+
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "Struct",
+			v:    secret,
+			want: map[string]interface{}{
+				"username": "admin",
+				"password": "aHVudGVyMg==",
+				"port":     5432,
+				"timeout":  "1m30s",
+				"replica":  "db-2",
+				"pool":     map[string]interface{}{"size": 10},
+				"Tags":     []string{"a"},
+			},
+		},
+		{
+			name: "OmitEmpty",
+			v:    &testDatabaseSecret{Username: "admin"},
+			want: map[string]interface{}{
+				"username": "admin",
+				"password": nil,
+				"port":     0,
+				"pool":     map[string]interface{}{"size": 0},
+				"Tags":     []string(nil),
+			},
+		},
+		{name: "Required", v: &testDatabaseSecret{}, wantErr: `required secret key "username" is empty`},
+		{
+			name: "RequiredZeroValues",
+			v: &struct {
+				Port    int  `vault:"port,required"`
+				Enabled bool `vault:"enabled,required"`
+			}{},
+			want: map[string]interface{}{"port": 0, "enabled": false},
+		},
+		{
+			name: "RequiredNilSlice",
+			v: &struct {
+				Hosts []string `vault:"hosts,required"`
+			}{},
+			wantErr: `required secret key "hosts" is empty`,
+		},
+		{name: "NotStruct", v: "foo", wantErr: "want a struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalSecret(tt.v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("marshalSecret() error = %v, wantErr %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("marshalSecret() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("marshalSecret() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unmarshalSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		check   func(t *testing.T, got *testDatabaseSecret)
+		wantErr string
+	}{
+		{
+			name: "Secret",
+			data: `{"username":"admin","password":"aHVudGVyMg==","port":5432,"timeout":"1m30s","replica":"db-2",
+				"pool":{"size":10,"warm":true},"Tags":["a","b"],"-":"ignored"}`,
+			check: func(t *testing.T, got *testDatabaseSecret) {
+				if got.Username != "admin" || string(got.Password) != "hunter2" || got.Port != 5432 ||
+					got.Timeout != 90*time.Second || got.Replica == nil || *got.Replica != "db-2" ||
+					got.Pool.Size != 10 || !got.Pool.Warm || len(got.Tags) != 2 || got.Internal != "" {
+					t.Errorf("unmarshalSecret() got = %+v", got)
+				}
+			},
+		},
+		{
+			name: "DurationSeconds",
+			data: `{"username":"admin","timeout":30}`,
+			check: func(t *testing.T, got *testDatabaseSecret) {
+				if got.Timeout != 30*time.Second {
+					t.Errorf("unmarshalSecret() timeout = %v, want 30s", got.Timeout)
+				}
+			},
+		},
+		{name: "Required", data: `{"port":5432}`, wantErr: `required secret key "username" is missing`},
+		{name: "InvalidBase64", data: `{"username":"admin","password":"!"}`, wantErr: `secret key "password"`},
+		{name: "InvalidNested", data: `{"username":"admin","pool":"big"}`, wantErr: `secret key "pool"`},
+		{name: "InvalidType", data: `{"username":"admin","port":"high"}`, wantErr: `secret key "port"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatal(err)
+			}
+			got := new(testDatabaseSecret)
+			err := unmarshalSecret(data, got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("unmarshalSecret() error = %v, wantErr %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unmarshalSecret() error = %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func Test_kvv2Impl_WriteSecretFrom_ReadSecretInto(t *testing.T) {
+	var stored map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			var body struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			stored = body.Data
+			w.Write([]byte(`{"data":{"version":1}}`))
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": stored, "metadata": map[string]interface{}{"version": 1}},
+			})
+		}
+	})
+	k := c.KVv2()
+
+	want := testDatabaseSecret{Username: "admin", Password: []byte{0, 1, 2}, Timeout: time.Minute}
+	want.Pool.Size = 3
+	if _, err := k.WriteSecretFrom("db", &want, nil); err != nil {
+		t.Fatalf("WriteSecretFrom() error = %v", err)
+	}
+	var got testDatabaseSecret
+	if err := k.ReadSecretInto("db", &got); err != nil {
+		t.Fatalf("ReadSecretInto() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSecretInto() got = %+v, want %+v", got, want)
+	}
+}
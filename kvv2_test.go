@@ -1,9 +1,18 @@
 package govault
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var testClient *Client
@@ -128,15 +137,10 @@ func Test_kvv2ClientImpl_ReadSecretVersion(t *testing.T) {
 				version: 1,
 			},
 			want: &KVv2Secret{
-				Data: map[string]string{
+				Data: map[string]interface{}{
 					"foo": "foo",
 				},
-				Metadata: struct {
-					CreatedTime  string `json:"created_time"`
-					DeletionTime string `json:"deletion_time"`
-					Destroyed    bool   `json:"destroyed"`
-					Version      int    `json:"version"`
-				}{},
+				Metadata: KVv2SecretVersionMetadata{},
 			},
 			wantErr: false,
 		},
@@ -159,6 +163,98 @@ func Test_kvv2ClientImpl_ReadSecretVersion(t *testing.T) {
 	}
 }
 
+func Test_kvv2Impl_ReadSecretVersion_Query(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   int
+		wantQuery string
+	}{
+		{"Latest", 0, ""},
+		{"Specific", 3, "version=3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.RawQuery
+				w.Write([]byte(`{"data":{"data":{"foo":"bar"},"metadata":{"version":3}}}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+			if _, err := c.KVv2().ReadSecretVersion("foo", tt.version); err != nil {
+				t.Fatalf("ReadSecretVersion() error = %v", err)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("ReadSecretVersion() query = %q, want %q", gotQuery, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_ReadSecretVersionCtx(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	type fields struct {
+		client    *Client
+		MountPath string
+	}
+	type args struct {
+		ctx     context.Context
+		path    string
+		version int
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+	}{
+		{
+			name: "Canceled",
+			fields: fields{
+				client:    testClient,
+				MountPath: DefaultKVv2MountPath,
+			},
+			args: args{
+				ctx:     canceled,
+				path:    "foo",
+				version: 1,
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "DeadlineExceeded",
+			fields: fields{
+				client:    testClient,
+				MountPath: DefaultKVv2MountPath,
+			},
+			args: args{
+				ctx:     expired,
+				path:    "foo",
+				version: 1,
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &kvv2Impl{
+				client:    tt.fields.client,
+				MountPath: tt.fields.MountPath,
+			}
+			_, err := k.ReadSecretVersionCtx(tt.args.ctx, tt.args.path, tt.args.version)
+			var canceledErr *ErrCanceled
+			if !errors.As(err, &canceledErr) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadSecretVersionCtx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func Test_kvv2Impl_CreateOrUpdateSecret(t *testing.T) {
 	type fields struct {
 		client    *Client
@@ -166,7 +262,7 @@ func Test_kvv2Impl_CreateOrUpdateSecret(t *testing.T) {
 	}
 	type args struct {
 		path    string
-		data    map[string]string
+		data    map[string]interface{}
 		options *KVv2CreateOrUpdateSecretOptions
 	}
 	tests := []struct {
@@ -183,7 +279,7 @@ func Test_kvv2Impl_CreateOrUpdateSecret(t *testing.T) {
 			},
 			args: args{
 				path: "foo",
-				data: map[string]string{
+				data: map[string]interface{}{
 					"foo": "supersec",
 				},
 				options: &KVv2CreateOrUpdateSecretOptions{},
@@ -197,7 +293,7 @@ func Test_kvv2Impl_CreateOrUpdateSecret(t *testing.T) {
 				client:    tt.fields.client,
 				MountPath: tt.fields.MountPath,
 			}
-			if err := k.CreateOrUpdateSecret(tt.args.path, tt.args.data, tt.args.options); (err != nil) != tt.wantErr {
+			if _, err := k.CreateOrUpdateSecret(tt.args.path, tt.args.data, tt.args.options); (err != nil) != tt.wantErr {
 				t.Errorf("CreateOrUpdateSecret() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -409,3 +505,424 @@ func Test_kvv2Impl_ListSecrets(t *testing.T) {
 		})
 	}
 }
+
+func Test_kvv2Impl_ReadSecretMetadata(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/metadata/foo" {
+			t.Errorf("ReadSecretMetadata() path = %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data":{"cas_required":true,"created_time":"2021-01-01T00:00:00Z","current_version":2,
+			"custom_metadata":{"owner":"team-a"},"delete_version_after":"1h0m0s","max_versions":5,"oldest_version":1,
+			"updated_time":"2021-01-02T00:00:00Z","versions":{
+			"1":{"created_time":"2021-01-01T00:00:00Z","deletion_time":"2021-01-02T00:00:00Z","destroyed":true},
+			"2":{"created_time":"2021-01-02T00:00:00Z","deletion_time":"","destroyed":false}}}}`))
+	})
+	want := &KVv2Metadata{
+		CASRequired:        true,
+		CreatedTime:        "2021-01-01T00:00:00Z",
+		CurrentVersion:     2,
+		CustomMetadata:     map[string]string{"owner": "team-a"},
+		DeleteVersionAfter: "1h0m0s",
+		MaxVersions:        5,
+		OldestVersion:      1,
+		UpdatedTime:        "2021-01-02T00:00:00Z",
+		Versions: map[int]KVv2SecretVersionMetadata{
+			1: {CreatedTime: "2021-01-01T00:00:00Z", DeletionTime: "2021-01-02T00:00:00Z", Destroyed: true, Version: 1},
+			2: {CreatedTime: "2021-01-02T00:00:00Z", Version: 2},
+		},
+	}
+	got, err := c.KVv2().ReadSecretMetadata("foo")
+	if err != nil {
+		t.Fatalf("ReadSecretMetadata() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSecretMetadata() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_kvv2Impl_UpdateMetadata(t *testing.T) {
+	maxVersions := 5
+	casRequired := false
+	deleteVersionAfter := 90 * time.Minute
+	tests := []struct {
+		name     string
+		options  *KVv2UpdateMetadataOptions
+		wantBody map[string]interface{}
+	}{
+		{"Nil", nil, map[string]interface{}{}},
+		{
+			name: "All",
+			options: &KVv2UpdateMetadataOptions{
+				MaxVersions:        &maxVersions,
+				CASRequired:        &casRequired,
+				DeleteVersionAfter: &deleteVersionAfter,
+				CustomMetadata:     map[string]string{"owner": "team-a"},
+			},
+			wantBody: map[string]interface{}{
+				"max_versions":         float64(5),
+				"cas_required":         false,
+				"delete_version_after": "1h30m0s",
+				"custom_metadata":      map[string]interface{}{"owner": "team-a"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody map[string]interface{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/secret/metadata/foo" {
+					t.Errorf("UpdateMetadata() request = %s %s", r.Method, r.URL.Path)
+				}
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				w.WriteHeader(http.StatusNoContent)
+			})
+			if err := c.KVv2().UpdateMetadata("foo", tt.options); err != nil {
+				t.Fatalf("UpdateMetadata() error = %v", err)
+			}
+			if !reflect.DeepEqual(gotBody, tt.wantBody) {
+				t.Errorf("UpdateMetadata() body = %v, want %v", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_DeleteMetadataAndAllVersions(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v1/secret/metadata/foo" {
+			t.Errorf("DeleteMetadataAndAllVersions() request = %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if err := c.KVv2().DeleteMetadataAndAllVersions("foo"); err != nil {
+		t.Errorf("DeleteMetadataAndAllVersions() error = %v", err)
+	}
+}
+
+func Test_kvv2Impl_ReadSecretVersion_JSONValues(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"data":{"user":"admin","port":5432,"tls":true,"tags":["a","b"],"pool":{"size":10}},"metadata":{"version":1}}}`))
+	})
+	got, err := c.KVv2().ReadSecretVersion("db", 0)
+	if err != nil {
+		t.Fatalf("ReadSecretVersion() error = %v", err)
+	}
+	wantData := map[string]interface{}{
+		"user": "admin",
+		"port": float64(5432),
+		"tls":  true,
+		"tags": []interface{}{"a", "b"},
+		"pool": map[string]interface{}{"size": float64(10)},
+	}
+	if !reflect.DeepEqual(got.Data, wantData) {
+		t.Errorf("ReadSecretVersion() data = %v, want %v", got.Data, wantData)
+	}
+
+	type pool struct {
+		Size int `json:"size"`
+	}
+	var db struct {
+		User string   `json:"user"`
+		Port int      `json:"port"`
+		TLS  bool     `json:"tls"`
+		Tags []string `json:"tags"`
+		Pool pool     `json:"pool"`
+	}
+	if err := got.Decode(&db); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if db.User != "admin" || db.Port != 5432 || !db.TLS || len(db.Tags) != 2 || db.Pool.Size != 10 {
+		t.Errorf("Decode() got = %+v", db)
+	}
+}
+
+func Test_mergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target map[string]interface{}
+		patch  map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{"Add", map[string]interface{}{"a": "1"}, map[string]interface{}{"b": "2"}, map[string]interface{}{"a": "1", "b": "2"}},
+		{"Replace", map[string]interface{}{"a": "1"}, map[string]interface{}{"a": true}, map[string]interface{}{"a": true}},
+		{"Remove", map[string]interface{}{"a": "1", "b": "2"}, map[string]interface{}{"a": nil}, map[string]interface{}{"b": "2"}},
+		{
+			"Nested",
+			map[string]interface{}{"pool": map[string]interface{}{"size": 1, "warm": true}},
+			map[string]interface{}{"pool": map[string]interface{}{"size": 2, "warm": nil}},
+			map[string]interface{}{"pool": map[string]interface{}{"size": 2}},
+		},
+		{"NestedNew", nil, map[string]interface{}{"pool": map[string]interface{}{"size": 2}}, map[string]interface{}{"pool": map[string]interface{}{"size": 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergePatch(tt.target, tt.patch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergePatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_PatchSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		patchCode int
+		options   *KVv2CreateOrUpdateSecretOptions
+		wantCAS   float64
+		wantData  map[string]interface{}
+	}{
+		{name: "Patch", patchCode: http.StatusOK, options: &KVv2CreateOrUpdateSecretOptions{CAS: 3}, wantCAS: 3},
+		{name: "Fallback", patchCode: http.StatusMethodNotAllowed, wantCAS: 3, wantData: map[string]interface{}{"a": "1", "b": "2"}},
+		{name: "FallbackCAS", patchCode: http.StatusMethodNotAllowed, options: &KVv2CreateOrUpdateSecretOptions{CAS: 2}, wantCAS: 2, wantData: map[string]interface{}{"a": "1", "b": "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCAS float64
+			var gotData map[string]interface{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Data    map[string]interface{} `json:"data"`
+					Options struct {
+						CAS float64 `json:"cas"`
+					} `json:"options"`
+				}
+				switch r.Method {
+				case http.MethodPatch:
+					if ct := r.Header.Get("Content-Type"); ct != "application/merge-patch+json" {
+						t.Errorf("PatchSecret() Content-Type = %q", ct)
+					}
+					json.NewDecoder(r.Body).Decode(&body)
+					gotCAS = body.Options.CAS
+					w.WriteHeader(tt.patchCode)
+					w.Write([]byte(`{"data":{"version":4}}`))
+				case http.MethodGet:
+					w.Write([]byte(`{"data":{"data":{"a":"1","b":"1"},"metadata":{"version":3}}}`))
+				case http.MethodPut:
+					json.NewDecoder(r.Body).Decode(&body)
+					gotCAS, gotData = body.Options.CAS, body.Data
+					w.Write([]byte(`{"data":{"version":4}}`))
+				}
+			})
+			got, err := c.KVv2().PatchSecret("foo", map[string]interface{}{"b": "2"}, tt.options)
+			if err != nil {
+				t.Fatalf("PatchSecret() error = %v", err)
+			}
+			if got.Version != 4 {
+				t.Errorf("PatchSecret() version = %d, want 4", got.Version)
+			}
+			if gotCAS != tt.wantCAS {
+				t.Errorf("PatchSecret() cas = %v, want %v", gotCAS, tt.wantCAS)
+			}
+			if tt.wantData != nil && !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("PatchSecret() data = %v, want %v", gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+// kvv2MemServer is an in-memory KV v2 mount at "secret" supporting reads, check-and-set writes, metadata and
+// listing, shared by the KV v2 tests. Versions in deleted are treated as soft-deleted. before, if set, is called with
+// each request before it is handled, ie to simulate concurrent writers.
+type kvv2MemServer struct {
+	mu       sync.Mutex
+	versions map[string][]map[string]interface{}
+	metadata map[string]map[string]interface{}
+	deleted  map[string]map[int]bool
+	before   func(r *http.Request)
+}
+
+func newKVv2MemServer(t *testing.T) (*Client, *kvv2MemServer) {
+	s := &kvv2MemServer{
+		versions: map[string][]map[string]interface{}{},
+		metadata: map[string]map[string]interface{}{},
+		deleted:  map[string]map[int]bool{},
+	}
+	return newTestClient(t, s.handle), s
+}
+
+// put writes a new version of the secret at p.
+func (s *kvv2MemServer) put(p string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[p] = append(s.versions[p], data)
+}
+
+func (s *kvv2MemServer) handle(w http.ResponseWriter, r *http.Request) {
+	if s.before != nil {
+		s.before(r)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reply := func(code int, v interface{}) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(v)
+	}
+	notFound := func() { reply(http.StatusNotFound, map[string]interface{}{"errors": []string{}}) }
+
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/secret/")
+	kind, p := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		kind, p = rest[:i], rest[i+1:]
+	}
+	switch {
+	case kind == "metadata" && r.URL.Query().Get("list") == "true":
+		dir := strings.TrimSuffix(p, "/")
+		if dir != "" {
+			dir += "/"
+		}
+		seen := map[string]bool{}
+		var keys []string
+		for secret := range s.versions {
+			if !strings.HasPrefix(secret, dir) {
+				continue
+			}
+			key := strings.TrimPrefix(secret, dir)
+			if i := strings.Index(key, "/"); i >= 0 {
+				key = key[:i+1]
+			}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+	case kind == "metadata" && r.Method == http.MethodGet:
+		versions, ok := s.versions[p]
+		if !ok {
+			notFound()
+			return
+		}
+		vm := map[string]interface{}{}
+		for i := range versions {
+			deletion := ""
+			if s.deleted[p][i+1] {
+				deletion = "2021-01-01T00:00:00Z"
+			}
+			vm[strconv.Itoa(i+1)] = map[string]interface{}{"deletion_time": deletion}
+		}
+		data := map[string]interface{}{"current_version": len(versions), "oldest_version": 1, "versions": vm}
+		for k, v := range s.metadata[p] {
+			data[k] = v
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": data})
+	case kind == "metadata" && r.Method == http.MethodPost:
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.metadata[p] = body
+		w.WriteHeader(http.StatusNoContent)
+	case kind == "data" && r.Method == http.MethodGet:
+		versions := s.versions[p]
+		version := len(versions)
+		if v := r.URL.Query().Get("version"); v != "" {
+			version, _ = strconv.Atoi(v)
+		}
+		if version < 1 || version > len(versions) || s.deleted[p][version] {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"data":     versions[version-1],
+			"metadata": map[string]interface{}{"version": version},
+		}})
+	case kind == "data" && r.Method == http.MethodPut:
+		var body struct {
+			Data    map[string]interface{} `json:"data"`
+			Options map[string]interface{} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if cas, ok := body.Options["cas"].(float64); ok && int(cas) != len(s.versions[p]) {
+			reply(http.StatusBadRequest, map[string]interface{}{
+				"errors": []string{"check-and-set parameter did not match the current version"},
+			})
+			return
+		}
+		s.versions[p] = append(s.versions[p], body.Data)
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"version": len(s.versions[p])}})
+	default:
+		notFound()
+	}
+}
+
+func Test_kvv2Impl_UpdateSecret(t *testing.T) {
+	increment := func(current map[string]interface{}) (map[string]interface{}, error) {
+		if current == nil {
+			current = map[string]interface{}{}
+		}
+		count, _ := current["count"].(float64)
+		current["count"] = count + 1
+		return current, nil
+	}
+	tests := []struct {
+		name        string
+		data        map[string]interface{}
+		conflicts   int
+		wantVersion int
+		wantErr     bool
+	}{
+		{name: "Create", data: nil, wantVersion: 1},
+		{name: "Update", data: map[string]interface{}{"count": float64(1)}, wantVersion: 2},
+		{name: "Conflict", data: map[string]interface{}{"count": float64(1)}, conflicts: 2, wantVersion: 4},
+		{name: "TooManyConflicts", data: map[string]interface{}{"count": float64(1)}, conflicts: DefaultKVv2CASRetries + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, s := newKVv2MemServer(t)
+			if tt.data != nil {
+				s.put("foo", tt.data)
+			}
+			// other writers get there first for the given number of writes
+			conflicts := tt.conflicts
+			s.before = func(r *http.Request) {
+				if r.Method == http.MethodPut && conflicts > 0 {
+					conflicts--
+					s.put("foo", map[string]interface{}{"count": float64(10)})
+				}
+			}
+			got, err := c.KVv2().UpdateSecret("foo", increment)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !isCASMismatch(err) {
+					t.Errorf("UpdateSecret() error = %v, want a check-and-set conflict", err)
+				}
+				return
+			}
+			if got != tt.wantVersion {
+				t.Errorf("UpdateSecret() = %d, want %d", got, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_UpdateSecret_Error(t *testing.T) {
+	c, s := newKVv2MemServer(t)
+	s.put("foo", map[string]interface{}{"count": float64(1)})
+	wantErr := errors.New("invalid")
+	_, err := c.KVv2().UpdateSecret("foo", func(map[string]interface{}) (map[string]interface{}, error) {
+		return nil, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("UpdateSecret() error = %v, want %v", err, wantErr)
+	}
+	if got := len(s.versions["foo"]); got != 1 {
+		t.Errorf("UpdateSecret() wrote version %d after update error", got)
+	}
+}
+
+func Test_kvv2Impl_CreateOrUpdateSecret_Metadata(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"created_time":"2021-01-01T00:00:00Z","deletion_time":"","destroyed":false,"version":3}}`))
+	})
+	got, err := c.KVv2().CreateOrUpdateSecret("foo", map[string]interface{}{"foo": "bar"}, nil)
+	if err != nil {
+		t.Fatalf("CreateOrUpdateSecret() error = %v", err)
+	}
+	want := &KVv2SecretVersionMetadata{CreatedTime: "2021-01-01T00:00:00Z", Version: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateOrUpdateSecret() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultKVv2WalkConcurrency is how many LIST requests WalkSecrets runs at once by default.
+const DefaultKVv2WalkConcurrency = 4
+
+// ErrSkipSubtree is returned by a KVv2WalkFunc to skip the contents of the directory it was called with.
+var ErrSkipSubtree = errors.New("skip this subtree")
+
+type (
+	// KVv2WalkFunc is called by WalkSecrets for every directory and secret found, with its path relative to the
+	// mount. Directory paths end in "/". Returning ErrSkipSubtree for a directory skips its contents, and any other
+	// error stops the walk and is returned by WalkSecrets.
+	KVv2WalkFunc func(path string) error
+
+	// KVv2WalkOptions configure WalkSecrets and ListSecretsRecursive.
+	KVv2WalkOptions struct {
+		// Concurrency is how many directories are listed at once. Defaults to DefaultKVv2WalkConcurrency.
+		Concurrency int
+
+		// MaxDepth is how many directory levels below the root are walked, ie 1 only lists the root. Zero means no
+		// limit.
+		MaxDepth int
+
+		// Pattern filters the secrets passed to the walk function by matching their path with path.Match, ie
+		// "app/*/db". Directories are always passed.
+		Pattern string
+	}
+
+	secretWalker struct {
+		k       *kvv2Impl
+		ctx     context.Context
+		cancel  context.CancelFunc
+		fn      KVv2WalkFunc
+		options KVv2WalkOptions
+		sem     chan struct{}
+		wg      sync.WaitGroup
+
+		mu  sync.Mutex // serializes calls to fn and guards err
+		err error
+	}
+)
+
+// WalkSecrets walks the tree of secrets under root, listing directories concurrently. fn is never called
+// concurrently, but the order of the calls is unspecified. An empty mount is walked as an empty tree, while a root
+// directory that does not exist returns an ErrInvalidPath.
+// vault command: `vault kv list secret/{root}`, recursively
+func (k *kvv2Impl) WalkSecrets(root string, fn KVv2WalkFunc, options *KVv2WalkOptions) error {
+	return k.WalkSecretsCtx(context.Background(), root, fn, options)
+}
+
+func (k *kvv2Impl) WalkSecretsCtx(ctx context.Context, root string, fn KVv2WalkFunc, options *KVv2WalkOptions) error {
+	opts := KVv2WalkOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultKVv2WalkConcurrency
+	}
+	if opts.Pattern != "" {
+		if _, err := path.Match(opts.Pattern, ""); err != nil {
+			return err
+		}
+	}
+	root = strings.Trim(root, "/")
+	if root != "" {
+		root += "/"
+	}
+
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := &secretWalker{
+		k:       k,
+		ctx:     walkCtx,
+		cancel:  cancel,
+		fn:      fn,
+		options: opts,
+		sem:     make(chan struct{}, opts.Concurrency),
+	}
+	w.spawn(root, 1)
+	w.wg.Wait()
+
+	if w.err != nil {
+		return w.err
+	}
+	if ctx.Err() != nil {
+		return &ErrCanceled{Err: ctx.Err()}
+	}
+	return nil
+}
+
+// ListSecretsRecursive returns the paths of all secrets under root, relative to the mount, in sorted order.
+func (k *kvv2Impl) ListSecretsRecursive(root string, options *KVv2WalkOptions) ([]string, error) {
+	return k.ListSecretsRecursiveCtx(context.Background(), root, options)
+}
+
+func (k *kvv2Impl) ListSecretsRecursiveCtx(ctx context.Context, root string, options *KVv2WalkOptions) ([]string, error) {
+	var secrets []string
+	err := k.WalkSecretsCtx(ctx, root, func(p string) error {
+		if !strings.HasSuffix(p, "/") {
+			secrets = append(secrets, p)
+		}
+		return nil
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(secrets)
+	return secrets, nil
+}
+
+// spawn walks dir in a new goroutine once one of the Concurrency slots is free, so that waiting directories do not
+// each hold a goroutine. It returns false if the walk was stopped first.
+func (w *secretWalker) spawn(dir string, depth int) bool {
+	select {
+	case w.sem <- struct{}{}:
+	case <-w.ctx.Done():
+		return false
+	}
+	w.wg.Add(1)
+	go w.walk(dir, depth)
+	return true
+}
+
+// walk lists dir, which is empty or ends in "/", and descends into its subdirectories. It is started holding a slot
+// of w.sem, which it releases once dir is listed.
+func (w *secretWalker) walk(dir string, depth int) {
+	defer w.wg.Done()
+
+	keys, err := w.k.ListSecretsCtx(w.ctx, dir)
+	<-w.sem
+	if err != nil {
+		// directories deleted since their parent was listed are skipped, and an empty mount has nothing to list
+		if (depth > 1 || dir == "") && errors.Is(err, &ErrInvalidPath{}) {
+			return
+		}
+		w.fail(err)
+		return
+	}
+
+	for _, key := range keys {
+		p := dir + key
+		isDir := strings.HasSuffix(key, "/")
+		if !isDir && w.options.Pattern != "" {
+			if ok, _ := path.Match(w.options.Pattern, p); !ok {
+				continue
+			}
+		}
+		if err := w.call(p); err != nil {
+			if isDir && errors.Is(err, ErrSkipSubtree) {
+				continue
+			}
+			w.fail(err)
+			return
+		}
+		if isDir && (w.options.MaxDepth == 0 || depth < w.options.MaxDepth) && !w.spawn(p, depth+1) {
+			return
+		}
+	}
+}
+
+func (w *secretWalker) call(p string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	return w.fn(p)
+}
+
+// fail records the first error and stops the walk.
+func (w *secretWalker) fail(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+	w.cancel()
+}
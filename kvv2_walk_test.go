@@ -0,0 +1,181 @@
+package govault
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// kvv2TreeTestServer serves a fixed tree of secrets from a kvv2MemServer, reporting the most requests that were in
+// flight at once.
+func kvv2TreeTestServer(t *testing.T) (*Client, func() int) {
+	c, s := newKVv2MemServer(t)
+	for _, p := range []string{
+		"app/api/db", "app/api/cache", "app/web/db", "app/shared", "infra/ci", "infra/dns/zone", "root-secret",
+	} {
+		s.put(p, map[string]interface{}{"key": "value"})
+	}
+	var mu sync.Mutex
+	inflight, maxInflight := 0, 0
+	s.before = func(r *http.Request) {
+		mu.Lock()
+		inflight++
+		if inflight > maxInflight {
+			maxInflight = inflight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inflight--
+		mu.Unlock()
+	}
+	return c, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maxInflight
+	}
+}
+
+func Test_kvv2Impl_WalkSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    string
+		options *KVv2WalkOptions
+		skip    string
+		want    []string
+	}{
+		{
+			name: "All",
+			want: []string{
+				"app/", "app/api/", "app/api/cache", "app/api/db", "app/shared", "app/web/", "app/web/db",
+				"infra/", "infra/ci", "infra/dns/", "infra/dns/zone", "root-secret",
+			},
+		},
+		{
+			name: "Root",
+			root: "/app/",
+			want: []string{"app/api/", "app/api/cache", "app/api/db", "app/shared", "app/web/", "app/web/db"},
+		},
+		{
+			name:    "MaxDepth",
+			options: &KVv2WalkOptions{MaxDepth: 2},
+			want:    []string{"app/", "app/api/", "app/shared", "app/web/", "infra/", "infra/ci", "infra/dns/", "root-secret"},
+		},
+		{
+			name:    "Pattern",
+			options: &KVv2WalkOptions{Pattern: "app/*/db"},
+			want:    []string{"app/", "app/api/", "app/api/db", "app/web/", "app/web/db", "infra/", "infra/dns/"},
+		},
+		{
+			name: "SkipSubtree",
+			skip: "app/",
+			want: []string{"app/", "infra/", "infra/ci", "infra/dns/", "infra/dns/zone", "root-secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := kvv2TreeTestServer(t)
+			var got []string
+			err := c.KVv2().WalkSecrets(tt.root, func(p string) error {
+				got = append(got, p)
+				if p == tt.skip {
+					return ErrSkipSubtree
+				}
+				return nil
+			}, tt.options)
+			if err != nil {
+				t.Fatalf("WalkSecrets() error = %v", err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WalkSecrets() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_kvv2Impl_WalkSecrets_Concurrency(t *testing.T) {
+	c, maxInflight := kvv2TreeTestServer(t)
+	if err := c.KVv2().WalkSecrets("", func(string) error { return nil }, &KVv2WalkOptions{Concurrency: 2}); err != nil {
+		t.Fatalf("WalkSecrets() error = %v", err)
+	}
+	if got := maxInflight(); got > 2 {
+		t.Errorf("WalkSecrets() ran %d LIST requests at once, want at most 2", got)
+	}
+}
+
+func Test_kvv2Impl_WalkSecrets_Error(t *testing.T) {
+	c, _ := kvv2TreeTestServer(t)
+	wantErr := errors.New("stop")
+	err := c.KVv2().WalkSecrets("", func(p string) error {
+		if p == "app/api/db" {
+			return wantErr
+		}
+		return nil
+	}, nil)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("WalkSecrets() error = %v, want %v", err, wantErr)
+	}
+
+	if err := c.KVv2().WalkSecrets("missing", func(string) error { return nil }, nil); !errors.Is(err, &ErrInvalidPath{}) {
+		t.Errorf("WalkSecrets() error = %v, want %v", err, &ErrInvalidPath{})
+	}
+	if err := c.KVv2().WalkSecrets("", func(string) error { return nil }, &KVv2WalkOptions{Pattern: "["}); err == nil {
+		t.Error("WalkSecrets() error = nil, want bad pattern error")
+	}
+}
+
+func Test_kvv2Impl_ListSecretsRecursive(t *testing.T) {
+	c, _ := kvv2TreeTestServer(t)
+	got, err := c.KVv2().ListSecretsRecursive("infra", nil)
+	if err != nil {
+		t.Fatalf("ListSecretsRecursive() error = %v", err)
+	}
+	want := []string{"infra/ci", "infra/dns/zone"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSecretsRecursive() got = %v, want %v", got, want)
+	}
+}
+
+func Test_kvv2Impl_WalkSecrets_EmptyMount(t *testing.T) {
+	c, _ := newKVv2MemServer(t)
+	calls := 0
+	if err := c.KVv2().WalkSecrets("", func(string) error { calls++; return nil }, nil); err != nil || calls != 0 {
+		t.Errorf("WalkSecrets() error = %v with %d calls, want an empty walk", err, calls)
+	}
+	var buf bytes.Buffer
+	if n, err := c.KVv2().ExportMount(&buf, "", nil); err != nil || n != 0 {
+		t.Errorf("ExportMount() = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func Test_kvv2Impl_WalkSecrets_Goroutines(t *testing.T) {
+	c, s := newKVv2MemServer(t)
+	for i := 0; i < 300; i++ {
+		s.put(fmt.Sprintf("wide/dir-%d/secret", i), map[string]interface{}{"key": "value"})
+	}
+	var mu sync.Mutex
+	baseline, maxGoroutines := runtime.NumGoroutine(), 0
+	s.before = func(r *http.Request) {
+		mu.Lock()
+		if n := runtime.NumGoroutine(); n > maxGoroutines {
+			maxGoroutines = n
+		}
+		mu.Unlock()
+	}
+
+	if err := c.KVv2().WalkSecrets("", func(string) error { return nil }, &KVv2WalkOptions{Concurrency: 2}); err != nil {
+		t.Fatalf("WalkSecrets() error = %v", err)
+	}
+	// directories waiting for a LIST slot must not each hold a goroutine
+	if got := maxGoroutines - baseline; got > 50 {
+		t.Errorf("WalkSecrets() ran %d more goroutines than before the walk, want at most 50", got)
+	}
+}
@@ -0,0 +1,221 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const DefaultRenewFraction = 2.0 / 3.0
+
+type (
+	// LifetimeWatcherInput configures a LifetimeWatcher.
+	LifetimeWatcherInput struct {
+		// LeaseID is the lease to keep alive. When empty, the client's token is watched instead.
+		LeaseID string
+
+		// Increment is the TTL requested on each renewal. Zero lets Vault pick its default.
+		Increment time.Duration
+
+		// RenewFraction is the fraction of the TTL that elapses before each renewal. Defaults to
+		// DefaultRenewFraction.
+		RenewFraction float64
+
+		// ReLogin is called when the token or lease can no longer be extended because it reached its max TTL or
+		// is not renewable. It should obtain a new token, ie by calling Client.Login or Client.SetToken. When
+		// watching the client's token, the watcher then continues with the new token; otherwise it finishes.
+		// When nil, the watcher finishes once the max TTL is reached.
+		ReLogin func(ctx context.Context, c *Client) error
+	}
+
+	// RenewOutput is sent on LifetimeWatcher.RenewCh after every successful renewal.
+	RenewOutput struct {
+		RenewedAt time.Time
+		LeaseID   string
+		TTL       time.Duration
+		Renewable bool
+		Auth      *AuthResult
+	}
+
+	// LifetimeWatcher keeps a token or lease alive by renewing it in the background, see NewLifetimeWatcher.
+	LifetimeWatcher struct {
+		client  *Client
+		input   LifetimeWatcherInput
+		renewCh chan *RenewOutput
+		doneCh  chan error
+
+		stopCh   chan struct{}
+		stopOnce sync.Once
+	}
+)
+
+// ErrLifetimeWatcherStopped is sent on LifetimeWatcher.DoneCh when the watcher is stopped by Stop.
+var ErrLifetimeWatcherStopped = errors.New("lifetime watcher stopped")
+
+// NewLifetimeWatcher constructs a LifetimeWatcher that renews the token of client, or the lease input.LeaseID, at a
+// fraction of its TTL. Call Start to begin watching.
+func NewLifetimeWatcher(client *Client, input *LifetimeWatcherInput) (*LifetimeWatcher, error) {
+	if client == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if input == nil {
+		input = &LifetimeWatcherInput{}
+	}
+	w := &LifetimeWatcher{
+		client:  client,
+		input:   *input,
+		renewCh: make(chan *RenewOutput, 5),
+		doneCh:  make(chan error, 1),
+		stopCh:  make(chan struct{}),
+	}
+	if w.input.RenewFraction <= 0 || w.input.RenewFraction >= 1 {
+		w.input.RenewFraction = DefaultRenewFraction
+	}
+	return w, nil
+}
+
+// RenewCh receives an event after every successful renewal. Events are dropped if the channel is not drained.
+func (w *LifetimeWatcher) RenewCh() <-chan *RenewOutput {
+	return w.renewCh
+}
+
+// DoneCh receives exactly one value when the watcher finishes: nil if the max TTL was reached and there is no
+// ReLogin callback or if the token or lease does not expire (a TTL of 0, ie a root token), ErrLifetimeWatcherStopped
+// after Stop, an *ErrCanceled if the context passed to Start is done, or the error that made renewal or re-login fail.
+func (w *LifetimeWatcher) DoneCh() <-chan error {
+	return w.doneCh
+}
+
+// Start begins watching in a background goroutine. The watcher stops when ctx is done or Stop is called.
+func (w *LifetimeWatcher) Start(ctx context.Context) {
+	go func() {
+		w.doneCh <- w.run(ctx)
+	}()
+}
+
+// Stop stops the watcher. It is safe to call more than once.
+func (w *LifetimeWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+func (w *LifetimeWatcher) run(ctx context.Context) error {
+	for {
+		ttl, renewable, err := w.lookup(ctx)
+		if err != nil {
+			return err
+		}
+		if ttl <= 0 {
+			// a TTL of 0 means the token or lease never expires, so there is nothing to renew or log in again for
+			w.client.Logger.Debug("lifetime watcher: no TTL, nothing to renew")
+			return nil
+		}
+		if err := w.renewUntilMaxTTL(ctx, ttl, renewable); err != nil {
+			return err
+		}
+
+		// the token or lease can no longer be extended
+		if w.input.ReLogin == nil {
+			return nil
+		}
+		w.client.Logger.Debug("lifetime watcher: max TTL reached, logging in again")
+		if err := w.input.ReLogin(ctx, w.client); err != nil {
+			return err
+		}
+		if w.input.LeaseID != "" {
+			return nil
+		}
+	}
+}
+
+// renewUntilMaxTTL renews at a fraction of ttl until renewal no longer extends the TTL, then waits for the final
+// fraction of the remaining TTL to elapse.
+func (w *LifetimeWatcher) renewUntilMaxTTL(ctx context.Context, ttl time.Duration, renewable bool) error {
+	for renewable {
+		if err := w.sleep(ctx, w.fraction(ttl)); err != nil {
+			return err
+		}
+		out, err := w.renew(ctx)
+		if err != nil {
+			return err
+		}
+		select {
+		case w.renewCh <- out:
+		default:
+		}
+
+		// Vault caps the TTL at the max TTL, so a shorter TTL than requested means we are approaching it
+		capped := out.TTL < ttl || (w.input.Increment > 0 && out.TTL < w.input.Increment)
+		renewable = out.Renewable && !capped
+		ttl = out.TTL
+	}
+	return w.sleep(ctx, w.fraction(ttl))
+}
+
+func (w *LifetimeWatcher) fraction(ttl time.Duration) time.Duration {
+	return time.Duration(float64(ttl) * w.input.RenewFraction)
+}
+
+func (w *LifetimeWatcher) sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return &ErrCanceled{Err: ctx.Err()}
+	case <-w.stopCh:
+		return ErrLifetimeWatcherStopped
+	case <-t.C:
+		return nil
+	}
+}
+
+// lookup returns the current TTL and renewability of the watched token or lease.
+func (w *LifetimeWatcher) lookup(ctx context.Context) (time.Duration, bool, error) {
+	if w.input.LeaseID == "" {
+		info, err := w.client.TokenAuth().LookupSelfCtx(ctx)
+		if err != nil {
+			return 0, false, err
+		}
+		return time.Duration(info.TTL) * time.Second, info.Renewable, nil
+	}
+	body := map[string]interface{}{"lease_id": w.input.LeaseID}
+	r, err := w.client.doV1(ctx, http.MethodPut, "sys/leases/lookup", nil, body)
+	if err != nil {
+		return 0, false, err
+	}
+	w.client.Logger.Trace(r)
+	data := new(struct {
+		TTL       int  `json:"ttl"`
+		Renewable bool `json:"renewable"`
+	})
+	if err := typeConvert(r.Data, data); err != nil {
+		return 0, false, err
+	}
+	return time.Duration(data.TTL) * time.Second, data.Renewable, nil
+}
+
+func (w *LifetimeWatcher) renew(ctx context.Context) (*RenewOutput, error) {
+	if w.input.LeaseID == "" {
+		auth, err := w.client.TokenAuth().RenewSelfCtx(ctx, w.input.Increment)
+		if err != nil {
+			return nil, err
+		}
+		return &RenewOutput{RenewedAt: time.Now(), TTL: auth.TTL(), Renewable: auth.Renewable, Auth: auth}, nil
+	}
+	body := map[string]interface{}{"lease_id": w.input.LeaseID}
+	if w.input.Increment > 0 {
+		body["increment"] = int(w.input.Increment / time.Second)
+	}
+	r, err := w.client.doV1(ctx, http.MethodPut, "sys/leases/renew", nil, body)
+	if err != nil {
+		return nil, err
+	}
+	w.client.Logger.Trace(r)
+	return &RenewOutput{
+		RenewedAt: time.Now(),
+		LeaseID:   r.LeaseID,
+		TTL:       time.Duration(r.LeaseDuration) * time.Second,
+		Renewable: r.Renewable,
+	}, nil
+}
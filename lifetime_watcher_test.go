@@ -0,0 +1,142 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLifetimeWatcher(t *testing.T) {
+	var mu sync.Mutex
+	renewals := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			w.Write([]byte(`{"data":{"ttl":2,"renewable":true}}`))
+		case "/v1/auth/token/renew-self":
+			// the third renewal is capped by the max TTL
+			renewals++
+			ttl := 2
+			if renewals == 3 {
+				ttl = 1
+			}
+			fmt.Fprintf(w, `{"auth":{"client_token":"test","lease_duration":%d,"renewable":true}}`, ttl)
+		case "/v1/sys/leases/lookup":
+			w.Write([]byte(`{"data":{"ttl":2,"renewable":false}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	t.Run("ReLoginAtMaxTTL", func(t *testing.T) {
+		relogin := make(chan string, 1)
+		w, err := NewLifetimeWatcher(c, &LifetimeWatcherInput{
+			RenewFraction: 0.01,
+			ReLogin: func(ctx context.Context, c *Client) error {
+				c.SetToken("relogged")
+				relogin <- "relogged"
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Start(context.Background())
+		defer w.Stop()
+
+		for i := 0; i < 3; i++ {
+			select {
+			case out := <-w.RenewCh():
+				if out.Auth == nil {
+					t.Errorf("RenewCh() got = %+v, want auth", out)
+				}
+			case err := <-w.DoneCh():
+				t.Fatalf("DoneCh() error = %v before renewal %d", err, i+1)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for renewal %d", i+1)
+			}
+		}
+		select {
+		case <-relogin:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for re-login")
+		}
+
+		w.Stop()
+		if err := <-w.DoneCh(); !errors.Is(err, ErrLifetimeWatcherStopped) {
+			t.Errorf("DoneCh() error = %v, want %v", err, ErrLifetimeWatcherStopped)
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		w, err := NewLifetimeWatcher(c, &LifetimeWatcherInput{RenewFraction: 0.9})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		w.Start(ctx)
+		cancel()
+		if err := <-w.DoneCh(); !errors.Is(err, context.Canceled) {
+			t.Errorf("DoneCh() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("NonRenewableLeaseWithoutReLogin", func(t *testing.T) {
+		w, err := NewLifetimeWatcher(c, &LifetimeWatcherInput{LeaseID: "database/creds/app/abc", RenewFraction: 0.01})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Start(context.Background())
+		select {
+		case err := <-w.DoneCh():
+			if err != nil {
+				t.Errorf("DoneCh() error = %v, want nil", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for watcher to finish")
+		}
+	})
+}
+
+func TestLifetimeWatcher_NonExpiring(t *testing.T) {
+	var mu sync.Mutex
+	lookups := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lookups++
+		mu.Unlock()
+		w.Write([]byte(`{"data":{"ttl":0,"renewable":false}}`))
+	})
+	relogins := 0
+	w, err := NewLifetimeWatcher(c, &LifetimeWatcherInput{
+		ReLogin: func(ctx context.Context, c *Client) error {
+			relogins++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start(context.Background())
+	defer w.Stop()
+
+	select {
+	case err := <-w.DoneCh():
+		if err != nil {
+			t.Errorf("DoneCh() error = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watcher to finish")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if lookups != 1 || relogins != 0 {
+		t.Errorf("watcher made %d lookups and %d re-logins, want 1 and 0", lookups, relogins)
+	}
+}
@@ -0,0 +1,70 @@
+package govault
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// query builds the query string of a Vault API request. Values are encoded the way Vault expects them: booleans as
+// "true"/"false", numbers in base 10, and slices and arrays as one repeated key per element.
+type query url.Values
+
+func newQuery() query {
+	return query{}
+}
+
+// Set replaces any existing values of key with value.
+func (q query) Set(key string, value interface{}) query {
+	delete(q, key)
+	return q.Add(key, value)
+}
+
+// Add appends value to the values of key. A nil value is ignored.
+func (q query) Add(key string, value interface{}) query {
+	if value == nil {
+		return q
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if b, ok := value.([]byte); ok {
+			q[key] = append(q[key], string(b))
+			return q
+		}
+		for i := 0; i < rv.Len(); i++ {
+			q.Add(key, rv.Index(i).Interface())
+		}
+	default:
+		q[key] = append(q[key], encodeQueryValue(rv))
+	}
+	return q
+}
+
+// List sets list=true, which makes a GET request behave like a LIST request.
+func (q query) List() query {
+	return q.Set("list", true)
+}
+
+// Encode encodes the query in "URL encoded" form, sorted by key.
+func (q query) Encode() string {
+	return url.Values(q).Encode()
+}
+
+func encodeQueryValue(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(rv.Interface())
+	}
+}
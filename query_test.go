@@ -0,0 +1,28 @@
+package govault
+
+import "testing"
+
+func Test_query_Encode(t *testing.T) {
+	tests := []struct {
+		name string
+		q    query
+		want string
+	}{
+		{"Empty", newQuery(), ""},
+		{"Int", newQuery().Set("version", 3), "version=3"},
+		{"Bool", newQuery().Set("cas_required", false), "cas_required=false"},
+		{"List", newQuery().List(), "list=true"},
+		{"RepeatedKeys", newQuery().Add("versions", 1).Add("versions", 2), "versions=1&versions=2"},
+		{"Slice", newQuery().Set("versions", []int{1, 2, 3}), "versions=1&versions=2&versions=3"},
+		{"SetReplaces", newQuery().Add("key", "a").Add("key", "b").Set("key", "c"), "key=c"},
+		{"Escaped", newQuery().Set("path", "a b/c"), "path=a+b%2Fc"},
+		{"NilIgnored", newQuery().Set("key", nil), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Encode(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
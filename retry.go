@@ -0,0 +1,143 @@
+package govault
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryMinBackoff  = 500 * time.Millisecond
+	DefaultRetryMaxBackoff  = 10 * time.Second
+)
+
+// RetryPolicy controls how Client retries requests that fail with a transient error, such as a standby node
+// responding during a leader election or a sealed Vault. A nil *RetryPolicy disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts. The delay before attempt n+1 is
+	// MinBackoff*2^(n-1), capped at MaxBackoff, with the upper half randomized. A Retry-After header on the response
+	// replaces the computed delay but is capped at MaxBackoff too.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RetryableStatusCodes is the set of HTTP status codes that are retried.
+	RetryableStatusCodes map[int]bool
+
+	// RetryNonIdempotent allows writes (POST, PUT, PATCH) to be replayed after any retryable failure. By default
+	// writes are only replayed on responses Vault sends without processing the request, see
+	// unprocessedStatusCodes, since a write that failed in transit or with a server error may already have been
+	// applied. Leave this disabled unless every write made through the client is safe to repeat.
+	RetryNonIdempotent bool
+}
+
+// unprocessedStatusCodes are the responses Vault sends before processing a request: rate limiting, a performance
+// standby that cannot serve it, and a sealed or standby node. Writes that fail with one of them were not applied.
+var unprocessedStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	473:                           true,
+	http.StatusServiceUnavailable: true,
+}
+
+// NewDefaultRetryPolicy constructs a RetryPolicy that retries reads and deletes up to DefaultRetryMaxAttempts times
+// on 429, 473, 500, 502 and 503 responses and on transport errors. Writes are only retried on 429, 473 and 503.
+func NewDefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		MinBackoff:  DefaultRetryMinBackoff,
+		MaxBackoff:  DefaultRetryMaxBackoff,
+		RetryableStatusCodes: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			473:                            true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+		},
+	}
+}
+
+// shouldRetry reports whether a request made with method should be attempted again after attempt number attempt
+// failed. A statusCode of 0 means the request failed before a response was received.
+func (p *RetryPolicy) shouldRetry(method string, attempt, statusCode int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	if statusCode != 0 && !p.RetryableStatusCodes[statusCode] {
+		return false
+	}
+	if p.RetryNonIdempotent || isIdempotent(method) {
+		return true
+	}
+	// a write is only replayed if Vault reported that it did not process it
+	return unprocessedStatusCodes[statusCode]
+}
+
+// backoff returns how long to wait after attempt number attempt failed. A Retry-After header on the response takes
+// precedence over the computed exponential backoff, up to MaxBackoff.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+	d := float64(p.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	half := int64(d / 2)
+	if half <= 0 {
+		return time.Duration(d)
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// parseRetryAfter parses a Retry-After header value given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether a request with method is safe to repeat. PUT is not, since Vault treats it as an
+// alias for POST.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete, "LIST":
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepCtx waits for d to elapse, returning early with an *ErrCanceled if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return &ErrCanceled{Err: ctx.Err()}
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package govault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_doV1_Retry(t *testing.T) {
+	policy := NewDefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+
+	type args struct {
+		method   string
+		failures int
+		status   int
+		policy   *RetryPolicy
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name:         "RecoversFromSealed",
+			args:         args{method: http.MethodGet, failures: 2, status: http.StatusServiceUnavailable, policy: policy},
+			wantAttempts: 3,
+		},
+		{
+			name:         "GivesUpAfterMaxAttempts",
+			args:         args{method: http.MethodGet, failures: 5, status: http.StatusServiceUnavailable, policy: policy},
+			wantAttempts: 3,
+			wantErr:      &ErrSealed{},
+		},
+		{
+			name:         "DoesNotReplayPost",
+			args:         args{method: http.MethodPost, failures: 1, status: http.StatusBadGateway, policy: policy},
+			wantAttempts: 1,
+			wantErr:      &ErrThirdPartyError{},
+		},
+		{
+			name:         "DoesNotReplayPutAfterServerError",
+			args:         args{method: http.MethodPut, failures: 1, status: http.StatusInternalServerError, policy: policy},
+			wantAttempts: 1,
+			wantErr:      &ErrInternalServerError{},
+		},
+		{
+			name:         "ReplaysPutWhenSealed",
+			args:         args{method: http.MethodPut, failures: 1, status: http.StatusServiceUnavailable, policy: policy},
+			wantAttempts: 2,
+		},
+		{
+			name:         "DoesNotRetryForbidden",
+			args:         args{method: http.MethodGet, failures: 1, status: http.StatusForbidden, policy: policy},
+			wantAttempts: 1,
+			wantErr:      &ErrForbidden{},
+		},
+		{
+			name:         "NilPolicyDisablesRetries",
+			args:         args{method: http.MethodGet, failures: 1, status: http.StatusServiceUnavailable, policy: nil},
+			wantAttempts: 1,
+			wantErr:      &ErrSealed{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&attempts, 1)) <= tt.args.failures {
+					w.WriteHeader(tt.args.status)
+					return
+				}
+				w.Write([]byte(`{"data":{}}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+			c.RetryPolicy = tt.args.policy
+			_, err := c.doV1(context.Background(), tt.args.method, "secret/data/foo", nil, map[string]string{})
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("doV1() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("doV1() attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{"Empty", "", 0, false},
+		{"Seconds", "3", 3 * time.Second, true},
+		{"PastDate", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"Garbage", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff_RetryAfter(t *testing.T) {
+	policy := NewDefaultRetryPolicy()
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+	}{
+		{"Seconds", "2", 2 * time.Second},
+		{"CappedAtMaxBackoff", "3600", policy.MaxBackoff},
+		{"DateCappedAtMaxBackoff", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), policy.MaxBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{"Retry-After": []string{tt.retryAfter}}}
+			if got := policy.backoff(1, resp); got != tt.want {
+				t.Errorf("backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_doV1_RetryAppliedWrite(t *testing.T) {
+	policy := NewDefaultRetryPolicy()
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		method       string
+		wantAttempts int32
+	}{
+		{"Put", http.MethodPut, 1},
+		{"Get", http.MethodGet, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the server applies the request, then drops the connection before responding the first time
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Fatal(err)
+					}
+					conn.Close()
+					return
+				}
+				w.Write([]byte(`{"data":{}}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.Client(), srv.URL, "test", NewDiscardLogger())
+			c.RetryPolicy = policy
+			_, err := c.doV1(context.Background(), tt.method, "secret/data/foo", nil, map[string]string{})
+			if (err != nil) != (tt.wantAttempts == 1) {
+				t.Errorf("doV1() error = %v", err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("doV1() attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
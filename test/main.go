@@ -3,7 +3,7 @@ package main
 import "github.com/pbar1/govault"
 
 func main() {
-	// the default client looks for VAULT_ADDR and VAULT_TOKEN
+	// the default client reads the standard Vault environment variables, ie VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE, VAULT_CACERT
 	vault := govault.NewDefaultClient()
 
 	// read latest version of KV v2 secret "/secret/foo"
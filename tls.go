@@ -0,0 +1,223 @@
+package govault
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TLSConfig describes how Client connects to Vault over TLS. Build turns it into a *tls.Config and Transport into an
+// *http.Transport; Client.SetTLSConfig installs it on a Client.
+type TLSConfig struct {
+	// CACert is a PEM-encoded CA bundle file, CAPath a directory of PEM-encoded CA certificates and CACertBytes
+	// PEM-encoded CA certificates held in memory. When none are set, the system roots are used.
+	CACert      string
+	CAPath      string
+	CACertBytes []byte
+
+	// ClientCert and ClientKey are a PEM-encoded certificate and key presented to Vault for mutual TLS. The files
+	// are checked on every handshake and reloaded when they change, so rotated certificates are picked up without
+	// rebuilding the client.
+	ClientCert string
+	ClientKey  string
+
+	ServerName string
+	Insecure   bool
+
+	// MinVersion is the minimum TLS version accepted, ie tls.VersionTLS13. Defaults to tls.VersionTLS12.
+	MinVersion uint16
+}
+
+// ErrCertificate is returned by TLSConfig.Build when certificates cannot be loaded. Client is set if the client
+// certificate failed and unset if the CA certificates did; Err is the underlying error.
+type ErrCertificate struct {
+	Client bool
+	Err    error
+}
+
+// Build constructs a *tls.Config from t. Certificates that cannot be loaded are reported as an *ErrCertificate.
+func (t *TLSConfig) Build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.Insecure,
+		MinVersion:         t.MinVersion,
+	}
+	if tlsConfig.MinVersion == 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+
+	if t.CACert != "" || t.CAPath != "" || len(t.CACertBytes) > 0 {
+		pool, err := t.certPool()
+		if err != nil {
+			return nil, &ErrCertificate{Err: err}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.ClientCert != "" || t.ClientKey != "" {
+		if t.ClientCert == "" || t.ClientKey == "" {
+			return nil, &ErrCertificate{Client: true, Err: errors.New("client certificate and key must be set together")}
+		}
+		reloader, err := newCertReloader(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, &ErrCertificate{Client: true, Err: err}
+		}
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	}
+
+	return tlsConfig, nil
+}
+
+// Transport constructs an *http.Transport with the same defaults as http.DefaultTransport that uses t for TLS.
+func (t *TLSConfig) Transport() (*http.Transport, error) {
+	tlsConfig, err := t.Build()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// SetTLSConfig replaces the transport of the client's underlying *http.Client with one built from config. The
+// *http.Client passed to NewClient is not modified.
+func (c *Client) SetTLSConfig(config *TLSConfig) error {
+	transport, err := config.Transport()
+	if err != nil {
+		return err
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
+
+// hasClientCertificate reports whether the client's transport presents a client certificate. Custom transports
+// that are not an *http.Transport are assumed to.
+func (c *Client) hasClientCertificate() bool {
+	transport := c.httpClient.Transport
+	if transport == nil {
+		return false
+	}
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return true
+	}
+	return t.TLSClientConfig != nil &&
+		(len(t.TLSClientConfig.Certificates) > 0 || t.TLSClientConfig.GetClientCertificate != nil)
+}
+
+func (e *ErrCertificate) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ErrCertificate) Unwrap() error {
+	return e.Err
+}
+
+func (t *TLSConfig) certPool() (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if t.CACert != "" {
+		if err := appendCertsFromFile(pool, t.CACert); err != nil {
+			return nil, err
+		}
+	}
+	if t.CAPath != "" {
+		files, err := ioutil.ReadDir(t.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA path: %w", err)
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			if err := appendCertsFromFile(pool, filepath.Join(t.CAPath, f.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(t.CACertBytes) > 0 && !pool.AppendCertsFromPEM(t.CACertBytes) {
+		return nil, errors.New("no PEM certificates found in CA certificate bytes")
+	}
+	return pool, nil
+}
+
+func appendCertsFromFile(pool *x509.CertPool, file string) error {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("reading CA certificate: %w", err)
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no PEM certificates found in %s", file)
+	}
+	return nil
+}
+
+// certReloader serves a client certificate loaded from disk, reloading it whenever the modification time of the
+// certificate or key file changes.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate. If the files changed but cannot be loaded, for
+// example because they are mid-rotation, the previously loaded certificate is served.
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changed() {
+		_ = r.reload()
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) changed() bool {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return false
+	}
+	return !certInfo.ModTime().Equal(r.certMod) || !keyInfo.ModTime().Equal(r.keyMod)
+}
+
+func (r *certReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("loading client certificate: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading client certificate: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading client certificate: %w", err)
+	}
+	r.cert = &cert
+	r.certMod = certInfo.ModTime()
+	r.keyMod = keyInfo.ModTime()
+	return nil
+}
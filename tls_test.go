@@ -0,0 +1,160 @@
+package govault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and key with the given common name to dir, returning their paths
+// and the PEM-encoded certificate.
+func writeTestCert(t *testing.T, dir, commonName string) (certFile, keyFile string, certPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, certPEM
+}
+
+func TestTLSConfig_Build(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, certPEM := writeTestCert(t, dir, "first")
+
+	tests := []struct {
+		name    string
+		config  *TLSConfig
+		wantErr bool
+	}{
+		{"Empty", &TLSConfig{}, false},
+		{"CACertFile", &TLSConfig{CACert: certFile}, false},
+		{"CAPath", &TLSConfig{CAPath: dir}, true}, // the key file in dir is not a certificate
+		{"CACertBytes", &TLSConfig{CACertBytes: certPEM}, false},
+		{"InvalidCACertBytes", &TLSConfig{CACertBytes: []byte("nope")}, true},
+		{"ClientCert", &TLSConfig{ClientCert: certFile, ClientKey: keyFile}, false},
+		{"ClientCertWithoutKey", &TLSConfig{ClientCert: certFile}, true},
+		{"MissingCACert", &TLSConfig{CACert: filepath.Join(dir, "missing.pem")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.MinVersion != tls.VersionTLS12 {
+				t.Errorf("Build() MinVersion = %x, want %x", got.MinVersion, tls.VersionTLS12)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_Build_ErrCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, _, _ := writeTestCert(t, dir, "first")
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name       string
+		config     *TLSConfig
+		wantClient bool
+	}{
+		{"MissingCACert", &TLSConfig{CACert: missing, ClientCert: certFile}, false},
+		{"MissingClientCert", &TLSConfig{CACert: certFile, ClientCert: missing, ClientKey: missing}, true},
+		{"ClientCertWithoutKey", &TLSConfig{ClientCert: certFile}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.config.Build()
+			var certErr *ErrCertificate
+			if !errors.As(err, &certErr) {
+				t.Fatalf("Build() error = %v, want *ErrCertificate", err)
+			}
+			if certErr.Client != tt.wantClient {
+				t.Errorf("Build() error Client = %v, want %v", certErr.Client, tt.wantClient)
+			}
+		})
+	}
+}
+
+func Test_certReloader_GetClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, _ := writeTestCert(t, dir, "first")
+
+	tlsConfig, err := (&TLSConfig{ClientCert: certFile, ClientKey: keyFile}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonName := func() string {
+		cert, err := tlsConfig.GetClientCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf.Subject.CommonName
+	}
+
+	if got := commonName(); got != "first" {
+		t.Errorf("GetClientCertificate() common name = %q, want %q", got, "first")
+	}
+
+	// rotate the certificate on disk, making sure the modification time moves
+	writeTestCert(t, dir, "second")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, future, future)
+	os.Chtimes(keyFile, future, future)
+
+	if got := commonName(); got != "second" {
+		t.Errorf("GetClientCertificate() common name = %q, want %q", got, "second")
+	}
+}